@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/arshvin/tf-plan-reporter/internal"
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+	"github.com/arshvin/tf-plan-reporter/internal/report"
+)
+
+const (
+	atlantisCommand = "atlantis"
+
+	// Exit code of the App in Atlantis mode, if critical resources removals are found. Atlantis treats any non-zero
+	// exit code of policy check step as failure, the dedicated one allows to tell it from errors (exit code 1)
+	atlantisPolicyFailureExitCode = 2
+
+	// Several projects are reported in the same Atlantis comment, so the snippet of every project is kept small
+	defaultAtlantisMaxSize = 10000
+)
+
+var (
+	atlantisFormat  string
+	atlantisMaxSize int
+)
+
+// atlantis function runs the App as step of Atlantis custom workflow: it reads the show files of the project
+// (`$SHOWFILE` by default, or the files given as args), prints the markdown snippet for Atlantis comment and exits with
+// non-zero code if critical resources removals are found, so that it might be used as `policy_check` step
+func atlantis(showFiles []string) error {
+	settings, err := loadConfig(atlantisCommand)
+	if err != nil {
+		return err
+	}
+
+	if err := internal.ValidatePolicy(settings); err != nil {
+		return err
+	}
+
+	if len(showFiles) == 0 {
+		showFile := os.Getenv("SHOWFILE")
+		if len(showFile) == 0 {
+			return errors.New("SHOWFILE environment variable is not set, the show files should be given as args then")
+		}
+
+		showFiles = []string{showFile}
+	}
+
+	dm := processing.NewDecisionMaker(settings)
+
+	collectedData := new(processing.ConsolidatedJson)
+	for _, showFile := range showFiles {
+		plan, err := processing.ReadPlanJson(showFile)
+		if err != nil {
+			return err
+		}
+
+		collectedData.Parse(atlantisProjectName(len(showFiles), showFile), plan, dm)
+	}
+
+	dm.Evaluate(collectedData)
+
+	snippet, err := report.RenderOutput(collectedData, settings, config.ReportOutput{Format: atlantisFormat, MaxSize: atlantisMaxSize})
+	if err != nil {
+		return err
+	}
+	fmt.Println(snippet)
+
+	// The verdict is already in the snippet, so nothing is logged not to litter the comment
+	if collectedData.CriticalRemovalsFound() {
+		return exitCode(atlantisPolicyFailureExitCode)
+	}
+
+	return nil
+}
+
+// atlantisProjectName function returns the name of Atlantis project the show file belongs to. If there are several
+// show files, their names are used instead
+func atlantisProjectName(showFilesAmount int, showFile string) string {
+	if showFilesAmount > 1 {
+		return showFile
+	}
+
+	name := os.Getenv("PROJECT_NAME")
+	if len(name) == 0 {
+		name = os.Getenv("REPO_REL_DIR")
+	}
+	if len(name) == 0 {
+		return showFile
+	}
+
+	if workspace := os.Getenv("WORKSPACE"); len(workspace) > 0 && workspace != "default" {
+		name = fmt.Sprintf("%s (%s)", name, workspace)
+	}
+
+	return name
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/arshvin/tf-plan-reporter/internal/report"
+)
+
+const (
+	checkCommand = "check"
+
+	// Exit code of `check` mode, if critical resources removals are found. It allows to tell the blocked gate from
+	// errors (exit code 1)
+	checkBlockedExitCode = 2
+)
+
+// check function collects TF plan files and prints the summary with the verdict of the gate only, without rendering
+// of the report. Returns non-zero exit code if critical resources removals are found
+func check(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("'%s' mode doesn't accept args, got: %v", checkCommand, args)
+	}
+
+	settings, _, collectedData, err := collectPlans(checkCommand)
+	if err != nil {
+		return err
+	}
+
+	summary, content, err := report.RenderSummary(collectedData, settings, "stdout")
+	if err != nil {
+		return err
+	}
+	fmt.Println(content)
+
+	if summary.Verdict == report.VerdictBlocked {
+		log.Error("There are critical resources removals in the plans")
+
+		return exitCode(checkBlockedExitCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	log "github.com/sirupsen/logrus"
+)
+
+// command is the subcommand of the App. The flags are global ones, they are parsed before the subcommand is run,
+// so the command gets only the positional args left after them
+type command struct {
+	usage    string //Positional args of the command, e.g. `<address>`
+	synopsis string
+	help     string
+	run      func(args []string) int
+}
+
+func (c *command) Help() string {
+	return strings.TrimSpace(fmt.Sprintf("Usage: %s %s\n\n  %s\n\n  All flags are global, see '%s --help'", appName, c.usage, c.help, appName))
+}
+
+func (c *command) Synopsis() string {
+	return c.synopsis
+}
+
+func (c *command) Run(args []string) int {
+	return c.run(args)
+}
+
+// exitCode is the error of the mode, which exits with the dedicated code, e.g. when the gate is blocked. The mode logs
+// the reason by itself, if it's needed
+type exitCode int
+
+func (e exitCode) Error() string {
+	return fmt.Sprintf("exit code %d", int(e))
+}
+
+// finished function adapts the mode to the command, which returns exit code: 0 if the mode succeeded, the dedicated
+// code of exitCode error, or 1 for other errors, which are logged
+func finished(mode func(args []string) error) func(args []string) int {
+	return func(args []string) int {
+		err := mode(args)
+		if err == nil {
+			return 0
+		}
+
+		var code exitCode
+		if errors.As(err, &code) {
+			return int(code)
+		}
+
+		log.Error(err)
+
+		return 1
+	}
+}
+
+// commands function returns factories of all subcommands of the App by their names
+func commands() map[string]cli.CommandFactory {
+	all := map[string]*command{
+		reportCommand: {
+			usage:    reportCommand + " [flags]",
+			synopsis: "Render the report of planned changes (default command)",
+			help:     "Collects TF plan files and renders the report to Stdout and to requested outputs, publishes it and sends notifications.\n  It's run when no command is given, e.g. './tf-plan-reporter --config-file config.yml'",
+			run:      finished(renderReport),
+		},
+		checkCommand: {
+			usage:    checkCommand + " [flags]",
+			synopsis: "Evaluate the gate and print the verdict only",
+			help:     "Collects TF plan files, prints the summary with the verdict of the gate and exits with non-zero code if critical resources removals are found.",
+			run:      finished(check),
+		},
+		explainCommand: {
+			usage:    explainCommand + " [flags] <address>",
+			synopsis: "Explain why the change of resource is allowed or blocked",
+			help:     "Collects TF plan files and shows the decisions taken over the planned change of the resource: baseline, approvals and the policy of its module.",
+			run:      finished(explain),
+		},
+		validateConfigCommand: {
+			usage:    validateConfigCommand + " [flags]",
+			synopsis: "Check the config without collecting of plans",
+			help:     "Checks the config files, per-directory overrides, the baseline and approvals files. With '--print-schema' flag prints JSON Schema of the config file.",
+			run:      finished(func([]string) error { return validateConfig() }),
+		},
+		effectiveConfigCommand: {
+			usage:    effectiveConfigCommand + " [flags] [<module>...]",
+			synopsis: "Print the merged config and the policy of modules",
+			help:     "Prints the config after merging of included files and applying of the profile, and the effective policy of every module.",
+			run:      finished(effectiveConfig),
+		},
+		initCommand: {
+			usage:    initCommand + " [flags] [<folder>]",
+			synopsis: "Generate the starter config from discovered plans",
+			help:     "Scans the folder for TF plan files and writes the starter config with found resource types sorted into critical and allowed ones.",
+			run:      finished(initConfig),
+		},
+		matrixCommand: {
+			usage:    matrixCommand + " [flags]",
+			synopsis: "Compare the plans of several environments",
+			help:     "Collects the plans of every environment ('environments' config file parameter or '--env' flags) and renders the matrix report.",
+			run:      finished(func([]string) error { return matrix() }),
+		},
+		diffCommand: {
+			usage:    diffCommand + " [flags] <old> <new>",
+			synopsis: "Compare planned changes of two runs",
+			help:     "Compares planned changes of two runs, each of them is either the JSON report or the folder with plan files.",
+			run:      finished(diff),
+		},
+		atlantisCommand: {
+			usage:    atlantisCommand + " [flags] [<showfile>...]",
+			synopsis: "Run as Atlantis custom workflow step",
+			help:     "Reads the show files of Atlantis project ($SHOWFILE by default), prints the snippet for Atlantis comment and fails on critical resources removals.",
+			run:      finished(atlantis),
+		},
+		serveCommand: {
+			usage:    serveCommand + " [flags]",
+			synopsis: "Run HTTP server evaluating uploaded plans",
+			help:     "Runs HTTP server, which evaluates terraform plans with the policy of config file, as Terraform Cloud run task or via HTTP API.",
+			run:      finished(func([]string) error { return serve() }),
+		},
+		versionCommand: {
+			usage:    versionCommand,
+			synopsis: "Print the version and build info",
+			help:     "Prints the version of the App, and the Go version, platform and VCS revision it's built with.",
+			run:      finished(printVersion),
+		},
+	}
+
+	factories := make(map[string]cli.CommandFactory)
+	for name, c := range all {
+		factories[name] = func() (cli.Command, error) {
+			return c, nil
+		}
+	}
+
+	return factories
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/arshvin/tf-plan-reporter/internal"
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+	"github.com/arshvin/tf-plan-reporter/internal/report"
+)
+
+const (
+	diffCommand = "diff"
+)
+
+// diff function compares planned changes of two runs, each of them is either the JSON report or the folder with plan
+// files. The difference is printed to Stdout, and written in markdown format to the report file, if it's specified
+func diff(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("'%s' mode requires exactly 2 args: the old and the new JSON report or plan files folder", diffCommand)
+	}
+
+	// The config is needed only for comparing of plan files folders
+	var settings *config.AppConfig
+	loadSettings := func() (*config.AppConfig, error) {
+		if settings != nil {
+			return settings, nil
+		}
+
+		loaded, err := loadConfig(diffCommand)
+		if err != nil {
+			return nil, err
+		}
+		if err := internal.Validate(loaded); err != nil {
+			return nil, err
+		}
+		settings = loaded
+
+		return settings, nil
+	}
+
+	var reports []*report.JsonReport
+	for _, arg := range args {
+		stat, err := os.Stat(arg)
+		if err != nil {
+			return err
+		}
+
+		if !stat.IsDir() {
+			document, err := report.ReadJsonReport(arg)
+			if err != nil {
+				return err
+			}
+
+			reports = append(reports, document)
+			continue
+		}
+
+		searchFolder, err := filepath.Abs(arg)
+		if err != nil {
+			return err
+		}
+
+		startedAt := time.Now()
+		loaded, err := loadSettings()
+		if err != nil {
+			return err
+		}
+
+		// Module names are relative to the folder of the arg, so are the keys of its overrides
+		s, err := loaded.ForSearchFolder(searchFolder)
+		if err != nil {
+			return err
+		}
+		dm := processing.NewDecisionMaker(s)
+		collectedData, err := processing.CollectBinaryData(searchFolder, s.TfPlanFileBasename, s.TfCmdBinaryFile, s.NotUseTfChDirArg, failIfNoTfPlanFound, dm)
+		if err != nil {
+			return err
+		}
+		dm.Evaluate(collectedData)
+
+		reports = append(reports, report.NewJsonReport(collectedData, startedAt))
+	}
+
+	planDiff := report.NewPlanDiff(reports[0], reports[1])
+
+	if len(outputFileName) > 0 {
+		content, err := report.RenderDiff(planDiff, "markdown", templatesDir)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(outputFileName, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	content, err := report.RenderDiff(planDiff, "stdout", templatesDir)
+	if err != nil {
+		return err
+	}
+	fmt.Println(content)
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+const (
+	effectiveConfigCommand = "effective-config"
+)
+
+// effectiveConfig function prints the config after merging of included files and applying of the profile, and the
+// effective policy of every module given as args (all modules with plan files in the search folder by default)
+func effectiveConfig(modules []string) error {
+	if !isConfigGiven() {
+		return fmt.Errorf("'%s' cli arg (or %s* environment variables and parameter flags) is mandatory for '%s' mode", configFileArg, config.EnvPrefix, effectiveConfigCommand)
+	}
+
+	settings, err := parseConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(modules) == 0 && len(settings.SearchFolder) > 0 {
+		found, err := processing.FindPlanModules(settings.SearchFolder, settings.TfPlanFileBasename)
+		if err != nil {
+			return err
+		}
+		modules = found
+	}
+
+	policies := make(map[string]interface{})
+	for _, module := range modules {
+		policies[module] = policyDocument(settings.PolicyFor(module))
+	}
+
+	document := map[string]interface{}{
+		"sources": settings.Sources,
+		"config":  settings.Effective,
+		"modules": policies,
+	}
+	if len(settings.Profile) > 0 {
+		document["profile"] = settings.Profile
+	}
+
+	content, err := yaml.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(string(content))
+
+	return nil
+}
+
+// policyDocument function returns the policy of the module in the form of config file parameters
+func policyDocument(policy *config.ModulePolicy) map[string]interface{} {
+	var resources []string
+	for resourceType := range policy.ExceptionalResources {
+		resources = append(resources, resourceType)
+	}
+	slices.Sort(resources)
+
+	document := map[string]interface{}{}
+	if policy.IsAllCriticalSpecified {
+		document["critical_resources"] = []string{"all"}
+		document["allowed_removals"] = resources
+	} else {
+		document["critical_resources"] = resources
+	}
+
+	if len(policy.IgnoreRules) > 0 {
+		var rules []map[string]interface{}
+		for _, rule := range policy.IgnoreRules {
+			rules = append(rules, map[string]interface{}{"resource_type": rule.ResourceType, "attributes": rule.Attributes})
+		}
+		document["ignore_rules"] = rules
+	}
+
+	if len(policy.Overrides) > 0 {
+		document["overrides"] = policy.Overrides
+	}
+
+	return document
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	explainCommand = "explain"
+)
+
+// explain function collects TF plan files and prints the decisions taken over the planned change of the resource with
+// the address given as arg, in every module where it's found
+func explain(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("'%s' mode requires exactly 1 arg: the address of the resource", explainCommand)
+	}
+
+	_, dm, collectedData, err := collectPlans(explainCommand)
+	if err != nil {
+		return err
+	}
+
+	explanations, err := dm.Explain(collectedData, args[0])
+	if err != nil {
+		return err
+	}
+	if len(explanations) == 0 {
+		return fmt.Errorf("resource '%s' is not found in the plans", args[0])
+	}
+
+	var blocks []string
+	for _, explanation := range explanations {
+		resource := explanation.Resource
+
+		verdict := "ALLOWED"
+		if !explanation.Allowed {
+			verdict = "BLOCKED"
+		}
+
+		lines := []string{
+			fmt.Sprintf("%s (module: %s)", resource.Address, resource.Module),
+			fmt.Sprintf("  action: %s", resource.ActionLabel()),
+		}
+		for _, reason := range explanation.Reasons {
+			lines = append(lines, "  - "+reason)
+		}
+		lines = append(lines, "  verdict: "+verdict)
+
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+
+	fmt.Println(strings.Join(blocks, "\n\n"))
+
+	return nil
+}
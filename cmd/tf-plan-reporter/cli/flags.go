@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+)
+
+// outputsValue is the repeatable cli arg in form FORMAT=FILE, e.g. `--output html=report.html`
+type outputsValue []config.ReportOutput
+
+func (o *outputsValue) String() string {
+	var items []string
+	for _, output := range *o {
+		items = append(items, fmt.Sprintf("%s=%s", output.Format, output.FileName))
+	}
+
+	return strings.Join(items, ",")
+}
+
+func (o *outputsValue) Set(value string) error {
+	format, fileName, found := strings.Cut(value, "=")
+	if !found || len(format) == 0 || len(fileName) == 0 {
+		return fmt.Errorf("value must be in form FORMAT=FILE, got: '%s'", value)
+	}
+
+	*o = append(*o, config.ReportOutput{Format: format, FileName: fileName})
+
+	return nil
+}
+
+// environmentsValue is the repeatable cli arg in form NAME=FOLDER, e.g. `--env prod=envs/prod`
+type environmentsValue []config.Environment
+
+func (e *environmentsValue) String() string {
+	var items []string
+	for _, environment := range *e {
+		items = append(items, fmt.Sprintf("%s=%s", environment.Name, environment.SearchFolder))
+	}
+
+	return strings.Join(items, ",")
+}
+
+func (e *environmentsValue) Set(value string) error {
+	name, folder, found := strings.Cut(value, "=")
+	if !found || len(name) == 0 || len(folder) == 0 {
+		return fmt.Errorf("value must be in form NAME=FOLDER, got: '%s'", value)
+	}
+
+	*e = append(*e, config.Environment{Name: name, SearchFolder: folder})
+
+	return nil
+}
+
+// applyDecisionFiles function loads the approvals file given by `approvals` cli arg, if any
+func applyDecisionFiles(settings *config.AppConfig) error {
+	if len(approvalsFileName) == 0 {
+		return nil
+	}
+
+	approvals, err := config.LoadApprovals(approvalsFileName)
+	if err != nil {
+		return err
+	}
+
+	settings.Approvals = approvals
+
+	return nil
+}
+
+// parameterFlagNames are the names of dedicated cli flags of config file parameters, which differ from the parameters
+// names. The flags of other parameters are named after them, e.g. `--critical-resources`
+var parameterFlagNames = map[string]string{
+	"baseline_file": "baseline",
+}
+
+// defineParameterFlags function defines cli flag for every config file parameter, which has no dedicated one
+func defineParameterFlags() {
+	for _, parameter := range config.Parameters() {
+		if _, ok := parameterFlagNames[parameter.Name]; ok || flag.Lookup(parameter.FlagName()) != nil {
+			continue
+		}
+
+		usage := fmt.Sprintf("Overrides '%s' config file parameter and %s environment variable", parameter.Name, parameter.EnvName())
+		switch {
+		case parameter.Bool:
+			flag.Bool(parameter.FlagName(), false, usage)
+		case parameter.List:
+			flag.String(parameter.FlagName(), "", usage+". Comma separated list")
+		case parameter.Structured:
+			flag.String(parameter.FlagName(), "", usage+". YAML or JSON list")
+		default:
+			flag.String(parameter.FlagName(), "", usage)
+		}
+	}
+}
+
+// parameterFlags function returns values of the specified cli flags of config file parameters, keyed by parameter name
+func parameterFlags() map[string]string {
+	parameters := make(map[string]string)
+
+	for _, parameter := range config.Parameters() {
+		flagName, ok := parameterFlagNames[parameter.Name]
+		if !ok {
+			flagName = parameter.FlagName()
+		}
+
+		if pflag.CommandLine.Changed(flagName) {
+			parameters[parameter.Name] = pflag.CommandLine.Lookup(flagName).Value.String()
+		}
+	}
+
+	return parameters
+}
+
+// isConfigGiven function checks if the config is given by the config file, environment variables or cli flags
+func isConfigGiven() bool {
+	return len(configFileName) > 0 || config.IsSetByEnvironment() || len(parameterFlags()) > 0
+}
+
+// loadConfig function reads the config the mode evaluates the plans with, applying cli args of the templates folder
+// and the approvals file. The start time of the run is taken before the config is read
+func loadConfig(mode string) (*config.AppConfig, error) {
+	if !isConfigGiven() {
+		return nil, fmt.Errorf("'%s' cli arg (or %s* environment variables and parameter flags) is mandatory for '%s' mode", configFileArg, config.EnvPrefix, mode)
+	}
+
+	startedAt := time.Now()
+
+	settings, err := parseConfig()
+	if err != nil {
+		return nil, err
+	}
+	if len(templatesDir) > 0 {
+		settings.TemplatesDir = templatesDir
+	}
+	if err := applyDecisionFiles(settings); err != nil {
+		return nil, err
+	}
+	settings.StartedAt = startedAt
+
+	return settings, nil
+}
+
+// parseConfig function reads the config with precedence of layers (from the lowest): included files, the config file,
+// the profile, `TFPR_*` environment variables and cli flags
+func parseConfig() (*config.AppConfig, error) {
+	return config.LoadWithOptions(configFileName, config.Options{Profile: profileName, Parameters: parameterFlags()})
+}
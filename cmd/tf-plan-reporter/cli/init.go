@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+const (
+	initCommand           = "init"
+	defaultConfigFileName = "tf-plan-reporter.yml"
+)
+
+// initConfig function scans the folder given as arg (current working directory by default) for TF plan files and writes
+// the starter config file (`config-file` cli arg or `tf-plan-reporter.yml`). The resource types found in the plans are
+// sorted into critical and allowed ones by the built-in catalog of stateful resource types. If Stdin is a terminal,
+// the parameters which are not given by cli flags or environment variables are asked for
+func initConfig(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("'%s' mode accepts only one arg: the folder with plan files", initCommand)
+	}
+
+	given, err := config.LoadWithOptions("", config.Options{Parameters: parameterFlags()})
+	if err != nil {
+		return err
+	}
+
+	searchFolder := "."
+	if len(args) > 0 {
+		searchFolder = args[0]
+	} else if len(given.SearchFolder) > 0 {
+		searchFolder = given.SearchFolder
+	}
+
+	absSearchFolder, err := filepath.Abs(searchFolder)
+	if err != nil {
+		return err
+	}
+	if stat, err := os.Stat(absSearchFolder); err != nil || !stat.IsDir() {
+		return fmt.Errorf("search folder is not found: %s", searchFolder)
+	}
+
+	fileName := configFileName
+	if len(fileName) == 0 {
+		fileName = defaultConfigFileName
+	}
+
+	var prompt *bufio.Reader
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		prompt = bufio.NewReader(os.Stdin)
+	}
+
+	if _, err := os.Stat(fileName); err == nil {
+		if prompt == nil || !strings.EqualFold(ask(prompt, fmt.Sprintf("Config file %s already exists, overwrite it?", fileName), "n"), "y") {
+			return fmt.Errorf("config file already exists: %s", fileName)
+		}
+	}
+
+	starter := &config.Starter{
+		TfCmdBinaryFile:    given.TfCmdBinaryFile,
+		TfPlanFileBasename: given.TfPlanFileBasename,
+		SearchFolder:       searchFolder,
+	}
+
+	if len(starter.TfPlanFileBasename) == 0 {
+		starter.TfPlanFileBasename = processing.DetectPlanBasename(absSearchFolder)
+		if prompt != nil {
+			starter.TfPlanFileBasename = ask(prompt, "Base name of TF plan files", starter.TfPlanFileBasename)
+		}
+	}
+	if len(starter.TfPlanFileBasename) == 0 {
+		return fmt.Errorf("could not find terraform generated plan files in %s, their base name should be given by '--terraform-plan-file-basename' cli arg", searchFolder)
+	}
+
+	if len(starter.TfCmdBinaryFile) == 0 {
+		starter.TfCmdBinaryFile = "/usr/bin/terraform"
+		if found, err := exec.LookPath("terraform"); err == nil {
+			starter.TfCmdBinaryFile = found
+		}
+		if prompt != nil {
+			starter.TfCmdBinaryFile = ask(prompt, "Terraform binary file", starter.TfCmdBinaryFile)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return errors.New("could not get current working dir")
+	}
+
+	discovery, err := processing.DiscoverPlans(absSearchFolder, starter.TfPlanFileBasename, cwd)
+	if err != nil {
+		return err
+	}
+	starter.NotUseTfChDirArg = discovery.NotUseChDir()
+
+	readable := discovery.ProvidersNearPlans
+	switch {
+	case discovery.TerragruntCache:
+		starter.ChDirReason = "Plan files are found in terragrunt cache folders, so terraform is run in the folders of plan files"
+	case discovery.ProvidersNearPlans:
+		starter.ChDirReason = "Terraform providers folders are found next to plan files, so terraform is run in the folders of plan files"
+	case discovery.ProvidersInCwd:
+		starter.ChDirReason = "Terraform providers folder is found in current working directory, so terraform is run there"
+		readable = true
+	default:
+		starter.ChDirReason = "Terraform providers folders are not found, 'terraform init' should be run before the report"
+	}
+
+	log.WithFields(log.Fields{
+		"search_folder": searchFolder,
+		"plan_basename": starter.TfPlanFileBasename,
+		"total_amount":  len(discovery.PlanFiles),
+	}).Info("Found terraform generated plan files")
+
+	switch _, err := exec.LookPath(starter.TfCmdBinaryFile); {
+	case len(discovery.PlanFiles) == 0:
+		log.Warnf("There are no plan files with base name '%s', resource types are not collected", starter.TfPlanFileBasename)
+	case err != nil:
+		log.Warnf("Terraform binary file is not found: %s, resource types are not collected", starter.TfCmdBinaryFile)
+	case !readable:
+		log.Warn("Terraform providers folders are not found next to every plan file, resource types are not collected")
+	default:
+		collectedData, err := processing.CollectBinaryData(absSearchFolder, starter.TfPlanFileBasename, starter.TfCmdBinaryFile, starter.NotUseTfChDirArg, false, nil)
+		if err != nil {
+			return err
+		}
+		starter.ResourceTypes = collectedData.ResourceTypes()
+	}
+
+	content, err := starter.Render()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("Config file %s is written: %d critical and %d allowed for removal resource types found. Review it and check by '%s' mode\n",
+		fileName, len(starter.Critical()), len(starter.Allowed()), validateConfigCommand)
+
+	return nil
+}
+
+// ask function prints the question and returns the answer read from the terminal, or the default value if the answer
+// is empty
+func ask(prompt *bufio.Reader, question string, defaultValue string) string {
+	fmt.Printf("%s [%s]: ", question, defaultValue)
+
+	answer, _ := prompt.ReadString('\n')
+	if answer = strings.TrimSpace(answer); len(answer) > 0 {
+		return answer
+	}
+
+	return defaultValue
+}
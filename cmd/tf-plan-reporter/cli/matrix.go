@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/arshvin/tf-plan-reporter/internal"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+	"github.com/arshvin/tf-plan-reporter/internal/report"
+)
+
+const (
+	matrixCommand = "matrix"
+)
+
+var (
+	environments environmentsValue
+)
+
+// matrix function collects the plans of every environment separately and renders the matrix report of the same modules
+// across the environments
+func matrix() error {
+	settings, err := loadConfig(matrixCommand)
+	if err != nil {
+		return err
+	}
+	if len(environments) > 0 {
+		settings.Environments = environments
+	}
+	settings.FailIfNoTfPlanFound = failIfNoTfPlanFound
+
+	if len(settings.Environments) == 0 {
+		return errors.New("there are no environments to compare, they should be specified either by 'environments' config file parameter or by 'env' cli args")
+	}
+
+	if err := internal.Validate(settings); err != nil {
+		return err
+	}
+
+	var names []string
+	var collectedData []*processing.ConsolidatedJson
+	for _, environment := range settings.Environments {
+		searchFolder, err := filepath.Abs(environment.SearchFolder)
+		if err != nil {
+			return err
+		}
+
+		if stat, err := os.Stat(searchFolder); err != nil || !stat.IsDir() {
+			return fmt.Errorf("search folder of environment '%s' is not found: %s", environment.Name, searchFolder)
+		}
+
+		log.WithFields(log.Fields{
+			"environment":   environment.Name,
+			"search_folder": searchFolder,
+		}).Info("Collecting of environment plans")
+
+		// Module names are relative to the environment folder, so are the keys of its overrides
+		envSettings, err := settings.ForSearchFolder(searchFolder)
+		if err != nil {
+			return err
+		}
+		dm := processing.NewDecisionMaker(envSettings)
+
+		envData, err := processing.CollectBinaryData(
+			searchFolder,
+			settings.TfPlanFileBasename,
+			settings.TfCmdBinaryFile,
+			settings.NotUseTfChDirArg,
+			settings.FailIfNoTfPlanFound,
+			dm,
+		)
+		if err != nil {
+			return err
+		}
+		dm.Evaluate(envData)
+
+		names = append(names, environment.Name)
+		collectedData = append(collectedData, envData)
+	}
+
+	content, err := report.RenderMatrix(names, collectedData, settings.TemplatesDir)
+	if err != nil {
+		return err
+	}
+
+	if len(outputFileName) > 0 {
+		if err := os.WriteFile(outputFileName, []byte(content), 0644); err != nil {
+			return err
+		}
+	} else {
+		fmt.Print(content)
+	}
+
+	if failIfCriticalRemovals && slices.ContainsFunc(collectedData, (*processing.ConsolidatedJson).CriticalRemovalsFound) {
+		return errors.New("there are critical resources removal in some environment, while 'keep-gate' cli arg specified")
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+	"github.com/arshvin/tf-plan-reporter/internal/publish"
+	"github.com/arshvin/tf-plan-reporter/internal/report"
+)
+
+var (
+	publishTo          string
+	gitHubRepository   string
+	gitHubPullRequest  int
+	gitHubToken        string
+	gitLabProject      string
+	gitLabMergeRequest int
+	gitLabToken        string
+)
+
+// publishReport function posts the report to every publisher requested by `--publish` cli arg
+func publishReport(collectedData *processing.ConsolidatedJson, settings *config.AppConfig) error {
+	for _, name := range strings.Split(publishTo, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) == 0 {
+			continue
+		}
+
+		var publisher publish.Publisher
+		var format string
+		var err error
+
+		switch name {
+		case "github":
+			format = "github_markdown"
+			publisher, err = publish.NewGitHubPublisher(gitHubRepository, gitHubPullRequest, gitHubToken)
+		case "gitlab":
+			format = "gitlab_markdown"
+			publisher, err = publish.NewGitLabPublisher(gitLabProject, gitLabMergeRequest, gitLabToken)
+		default:
+			return fmt.Errorf("unknown publisher: '%s'", name)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		body, err := report.RenderBody(collectedData, settings, format, publish.MarkerSize())
+		if err != nil {
+			return err
+		}
+
+		log.WithField("publisher", publisher.Name()).Debug("Publishing of the report")
+
+		if err := publisher.Publish(body); err != nil {
+			return fmt.Errorf("%s publisher: %w", publisher.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// notify function posts notifications configured in `notifications` section of config file, taking into account
+// the verdicts each of them is interested in
+func notify(collectedData *processing.ConsolidatedJson, settings *config.AppConfig) error {
+	if len(settings.Notifications) == 0 {
+		return nil
+	}
+
+	verdict := report.NewSummary(collectedData, settings.StartedAt).Verdict
+
+	for _, notification := range settings.Notifications {
+		if len(notification.OnVerdicts) > 0 && !slices.Contains(notification.OnVerdicts, verdict) {
+			log.WithFields(log.Fields{
+				"type":    notification.Type,
+				"verdict": verdict,
+			}).Debug("The notification is skipped due to verdict")
+
+			continue
+		}
+
+		publisher, err := publish.NewWebhookPublisher(notification.Type, notification.WebhookUrl)
+		if err != nil {
+			return fmt.Errorf("%s notification: %w", notification.Type, err)
+		}
+
+		body, err := report.RenderBody(collectedData, settings, notification.Type, 0)
+		if err != nil {
+			return err
+		}
+
+		log.WithField("type", notification.Type).Debug("Posting of the notification")
+
+		if err := publisher.Publish(body); err != nil {
+			return fmt.Errorf("%s notification: %w", publisher.Name(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/arshvin/tf-plan-reporter/internal"
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+	"github.com/arshvin/tf-plan-reporter/internal/report"
+)
+
+const (
+	reportCommand = "report"
+)
+
+// collectPlans function reads and validates the config, collects TF plan files of the search folder and evaluates them.
+// It's the common part of the modes evaluating the plans of the search folder. The decision maker of the config is
+// returned as well
+func collectPlans(mode string) (*config.AppConfig, *processing.DecisionMaker, *processing.ConsolidatedJson, error) {
+	settings, err := loadConfig(mode)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := internal.Validate(settings); err != nil {
+		return nil, nil, nil, err
+	}
+
+	settings.ReportFileName = outputFileName
+	settings.HtmlReportFileName = htmlOutputFileName
+	settings.Outputs = append(settings.Outputs, reportOutputs...)
+	settings.SplitReports = splitReports
+	settings.GitHubActions = gitHubActions
+	settings.FailIfCriticalRemovals = failIfCriticalRemovals
+	settings.FailIfNoTfPlanFound = failIfNoTfPlanFound
+
+	dm := processing.NewDecisionMaker(settings) //Before the collecting, since parsing of plans depends on 'ignore_rules'
+
+	collectedData, err := processing.CollectBinaryData(
+		settings.SearchFolder,
+		settings.TfPlanFileBasename,
+		settings.TfCmdBinaryFile,
+		settings.NotUseTfChDirArg,
+		settings.FailIfNoTfPlanFound,
+		dm,
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	dm.Evaluate(collectedData)
+
+	return settings, dm, collectedData, nil
+}
+
+// renderReport function collects TF plan files and renders the report to Stdout and requested outputs, publishes it and
+// sends notifications
+func renderReport(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("'%s' mode doesn't accept args, got: %v", reportCommand, args)
+	}
+
+	settings, _, collectedData, err := collectPlans(reportCommand)
+	if err != nil {
+		return err
+	}
+
+	if err := report.PrintReport(collectedData, settings); err != nil {
+		return err
+	}
+
+	if err := publishReport(collectedData, settings); err != nil {
+		return err
+	}
+
+	if err := notify(collectedData, settings); err != nil {
+		return err
+	}
+
+	if settings.FailIfCriticalRemovals && collectedData.CriticalRemovalsFound() {
+		return errors.New("there are critical resources removal in the report, while 'keep-gate' cli arg specified")
+	}
+
+	return nil
+}
@@ -2,30 +2,41 @@ package cli
 
 import (
 	"flag"
+	"fmt"
 	"os"
 
+	"github.com/mitchellh/cli"
 	log "github.com/sirupsen/logrus"
 
-	"github.com/arshvin/tf-plan-reporter/internal"
 	"github.com/arshvin/tf-plan-reporter/internal/config"
-	"github.com/arshvin/tf-plan-reporter/internal/processing"
-	"github.com/arshvin/tf-plan-reporter/internal/report"
+	"github.com/arshvin/tf-plan-reporter/internal/publish"
+	"github.com/arshvin/tf-plan-reporter/internal/server"
+	"github.com/arshvin/tf-plan-reporter/internal/version"
 
 	"github.com/spf13/pflag"
-	"github.com/spf13/viper"
 )
 
 const (
+	appName = "tf-plan-reporter"
+
 	configFileArg         = "config-file"
 	printConfigExampleArg = "print-example"
 )
 
 var (
 	configFileName         string
+	profileName            string
 	outputFileName         string
+	htmlOutputFileName     string
+	templatesDir           string
+	baselineFileName       string
+	approvalsFileName      string
+	splitReports           bool
+	gitHubActions          bool
+	reportOutputs          outputsValue
 	onlyPrintConfigExample bool
-	failIfCriticalRemovals          bool
-	failIfNoTfPlanFound          bool
+	failIfCriticalRemovals bool
+	failIfNoTfPlanFound    bool
 	debugOutput            bool
 	noColor                bool
 )
@@ -34,23 +45,52 @@ var (
 func Execute() {
 
 	flag.StringVar(&configFileName, configFileArg, "", "Config file name of the App")
+	flag.StringVar(&profileName, "profile", "", "Profile of the config file applied on top of it, e.g. 'prod'")
 	flag.StringVar(&outputFileName, "report-file", "", "Output file name of the report ")
+	flag.StringVar(&htmlOutputFileName, "html-report-file", "", "Output file name of the standalone HTML report")
+	flag.Var(&reportOutputs, "output", "Additional report output in form FORMAT=FILE (FILE '-' means Stdout). Might be repeated")
+	flag.BoolVar(&splitReports, "split-report", false, "Split reports exceeding the size limit of their format to numbered part files, instead of truncating")
+	flag.BoolVar(&gitHubActions, "github-actions", false, "Write GitHub Actions job summary, step outputs and annotations. Turned on automatically inside of GitHub Actions")
+	flag.StringVar(&publishTo, "publish", "", "Comma separated list of places where to publish the report as a sticky comment: github, gitlab")
+	flag.StringVar(&gitHubRepository, "github-repository", "", "GitHub repository in form OWNER/NAME. Default is GITHUB_REPOSITORY environment variable")
+	flag.IntVar(&gitHubPullRequest, "github-pull-request", 0, "GitHub pull request number. Default is detected from GitHub Actions environment")
+	flag.StringVar(&gitHubToken, "github-token", "", "GitHub token. Default is GITHUB_TOKEN environment variable")
+	flag.StringVar(&gitLabProject, "gitlab-project", "", "GitLab project ID or path. Default is CI_PROJECT_ID environment variable")
+	flag.IntVar(&gitLabMergeRequest, "gitlab-merge-request", 0, "GitLab merge request IID. Default is CI_MERGE_REQUEST_IID environment variable")
+	flag.StringVar(&gitLabToken, "gitlab-token", "", "GitLab token with 'api' scope. Default is GITLAB_TOKEN environment variable")
+	flag.StringVar(&templatesDir, "templates-dir", "", "Folder with report templates overriding built-in ones. Overrides 'templates_dir' config file parameter")
+	flag.StringVar(&approvalsFileName, "approvals", "", "File with approvals of resources removals, e.g. supplied by pull request labels or ChatOps step")
+	flag.StringVar(&baselineFileName, "baseline", "", "Baseline file with accepted planned changes. Overrides 'baseline_file' config file parameter")
+	flag.BoolVar(&printSchema, "print-schema", false, "Print JSON Schema of the config file in 'validate-config' mode")
 	flag.BoolVar(&onlyPrintConfigExample, printConfigExampleArg, false, "Print an example of the App config file without analyses run")
 	flag.BoolVar(&failIfCriticalRemovals, "keep-gate", false, "Exit with non-zero code if critical resources removals found")
 	flag.BoolVar(&failIfNoTfPlanFound, "zero-plan-fail", false, "Exit with non-zero code if TF plan file not found")
+	flag.StringVar(&listenAddress, "listen", ":8080", "Address to listen HTTP requests on in 'serve' mode")
+	flag.Var(&environments, "env", "Environment compared in 'matrix' mode in form NAME=FOLDER. Might be repeated, overrides 'environments' config file parameter")
+	flag.StringVar(&atlantisFormat, "atlantis-format", "github_markdown", "Report format of the snippet printed in 'atlantis' mode")
+	flag.IntVar(&atlantisMaxSize, "atlantis-max-size", defaultAtlantisMaxSize, "Max size of the snippet printed in 'atlantis' mode in characters")
+	flag.Int64Var(&maxRequestSize, "max-request-size", server.DefaultMaxRequestSize, "Max size of uploaded plans in bytes in 'serve' mode")
+	flag.Int64Var(&maxPlanSize, "max-plan-size", publish.DefaultMaxPlanSize, "Max size of plans downloaded from Terraform Cloud in bytes in 'serve' mode")
+	flag.DurationVar(&reloadInterval, "config-reload-interval", server.DefaultReloadInterval, "How often the config file is checked for changes in 'serve' mode")
+	flag.StringVar(&tfcHmacKey, "tfc-hmac-key", "", "HMAC key of Terraform Cloud run task, requests must be signed with it in 'serve' mode. Default is TFC_RUN_TASK_HMAC_KEY environment variable")
+	flag.BoolVar(&insecureRunTask, "insecure-run-task", false, "Accept not signed run task requests in 'serve' mode, if the HMAC key is not set")
+	flag.StringVar(&tfcAddress, "tfc-address", server.DefaultTfcAddress, "Address of Terraform Cloud / Enterprise, run task URLs of other hosts are rejected in 'serve' mode")
 
 	flag.BoolVar(&debugOutput, "verbose", false, "Add debug logging output")
 	flag.BoolVar(&noColor, "no-color", false, "Turn off color output in log messages")
 
+	defineParameterFlags()
+
 	flag.Bool("help", false, "help message output")
 	flag.Bool("h", false, "help message output")
+	flag.Bool(versionCommand, false, "version output")
 
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 	pflag.CommandLine.MarkHidden("help")
 	pflag.CommandLine.MarkHidden("h")
+	pflag.CommandLine.MarkHidden(versionCommand)
 
 	pflag.Parse()
-	viper.BindPFlags(pflag.CommandLine)
 
 	if debugOutput {
 		log.SetLevel(log.DebugLevel)
@@ -62,9 +102,9 @@ func Execute() {
 		})
 	}
 
-	if ok, _ := pflag.CommandLine.GetBool("help"); ok {
-		pflag.Usage()
-		os.Exit(0) //Explicitly
+	help, _ := pflag.CommandLine.GetBool("help")
+	if short, _ := pflag.CommandLine.GetBool("h"); short {
+		help = true
 	}
 
 	if onlyPrintConfigExample {
@@ -72,36 +112,29 @@ func Execute() {
 		os.Exit(0) //Explicitly
 	}
 
-	if len(configFileName) > 0 {
-		settings := config.Parse(configFileName)
-		if err:= internal.Validate(settings); err != nil{
-			log.Fatal(err)
-		}
-
-		settings.ReportFileName = outputFileName
-		settings.FailIfCriticalRemovals = failIfCriticalRemovals
-		settings.FailIfNoTfPlanFound = failIfNoTfPlanFound
-
-		collectedData := processing.CollectBinaryData(
-			settings.SearchFolder,
-			settings.TfPlanFileBasename,
-			settings.TfCmdBinaryFile,
-			settings.NotUseTfChDirArg,
-			settings.FailIfNoTfPlanFound,
-		)
-
-		dm:=processing.GetDecisionMaker()
-		dm.SetConfig(settings)
-
-		report.PrintReport(collectedData,settings.ReportFileName)
+	args := pflag.Args()
+	if _, ok := commands()[pflag.Arg(0)]; !ok && isConfigGiven() {
+		args = append([]string{reportCommand}, args...) //Flat flags without the command, e.g. `--config-file config.yml`
+	}
+	if help {
+		args = append(args, "-h")
+	}
+	if ok, _ := pflag.CommandLine.GetBool(versionCommand); ok {
+		args = []string{versionCommand}
+	}
 
-		if settings.FailIfCriticalRemovals && dm.CriticalRemovalsFound() {
-			log.Fatal("There are critical resources removal in the report, while 'keep-gate' cli arg specified")
-		}
+	appCli := cli.NewCLI(appName, version.Version)
+	appCli.Args = args
+	appCli.Commands = commands()
+	appCli.HelpFunc = func(commands map[string]cli.CommandFactory) string {
+		return fmt.Sprintf("%s\nGlobal flags (might be given either before or after the command):\n%s",
+			cli.BasicHelpFunc(appName)(commands), pflag.CommandLine.FlagUsages())
+	}
 
-		os.Exit(0) //Explicitly
+	exitStatus, err := appCli.Run()
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	pflag.Usage()
-	log.Fatalf("At least one of the following flags must be chosen: %s, %s", configFileArg, printConfigExampleArg)
+	os.Exit(exitStatus)
 }
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/arshvin/tf-plan-reporter/internal/server"
+)
+
+const (
+	serveCommand = "serve"
+)
+
+var (
+	listenAddress   string
+	tfcHmacKey      string
+	insecureRunTask bool
+	tfcAddress      string
+	maxRequestSize  int64
+	maxPlanSize     int64
+	reloadInterval  time.Duration
+)
+
+// serve function runs the App as HTTP server, which evaluates terraform plans with the policy of config file,
+// as Terraform Cloud run task or via HTTP API
+func serve() error {
+	if len(configFileName) == 0 {
+		return fmt.Errorf("'%s' cli arg is mandatory for '%s' mode", configFileArg, serveCommand)
+	}
+
+	if len(tfcHmacKey) == 0 {
+		tfcHmacKey = os.Getenv("TFC_RUN_TASK_HMAC_KEY")
+	}
+
+	if len(tfcHmacKey) == 0 {
+		if !insecureRunTask {
+			return fmt.Errorf("'%s' mode requires HMAC key of run task ('--tfc-hmac-key' cli arg or TFC_RUN_TASK_HMAC_KEY environment variable), or '--insecure-run-task' cli arg to accept not signed requests", serveCommand)
+		}
+
+		log.Warn("Run task requests are not signed, anyone who can reach the server might trigger the requests to Terraform Cloud")
+	}
+
+	s, err := server.New(server.Options{
+		ConfigFile:      configFileName,
+		Profile:         profileName,
+		HmacKey:         tfcHmacKey,
+		InsecureRunTask: insecureRunTask,
+		TfcAddress:      tfcAddress,
+		MaxRequestSize:  maxRequestSize,
+		MaxPlanSize:     maxPlanSize,
+		ReloadInterval:  reloadInterval,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.ListenAndServe(listenAddress)
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/arshvin/tf-plan-reporter/internal"
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+const (
+	validateConfigCommand = "validate-config"
+)
+
+var (
+	printSchema bool
+)
+
+// validateConfig function checks the config files, per-directory overrides, the baseline and approvals files without
+// collecting of plans. Found problems are printed to Stderr one per line, with locations where it's possible
+func validateConfig() error {
+	if printSchema {
+		content, err := config.JSONSchema()
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(string(content))
+		return nil
+	}
+
+	if !isConfigGiven() {
+		return fmt.Errorf("'%s' cli arg (or %s* environment variables and parameter flags) is mandatory for '%s' mode", configFileArg, config.EnvPrefix, validateConfigCommand)
+	}
+
+	settings, err := config.LoadWithOptions(configFileName, config.Options{Profile: profileName, Parameters: parameterFlags()})
+	if err == nil && len(approvalsFileName) > 0 {
+		_, err = config.LoadApprovals(approvalsFileName)
+	}
+	if err == nil {
+		err = internal.Validate(settings)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err) //Problems are printed one per line, without the log prefix
+
+		return exitCode(1)
+	}
+
+	for _, expired := range processing.NewDecisionMaker(settings).ExpiredAcceptances() {
+		log.Warn(expired)
+	}
+
+	sources := "environment variables and cli flags"
+	if len(settings.Sources) > 0 {
+		sources = strings.Join(settings.Sources, ", ")
+	}
+	fmt.Printf("Config is valid: %s\n", sources)
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/arshvin/tf-plan-reporter/internal/version"
+)
+
+const (
+	versionCommand = "version"
+)
+
+// printVersion function prints the version of the App and the build info: Go version, platform and VCS revision
+func printVersion(args []string) error {
+	fmt.Printf("%s %s\n", appName, version.Version)
+	fmt.Printf("go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision", "vcs.time", "vcs.modified":
+				fmt.Printf("%s: %s\n", setting.Key, setting.Value)
+			}
+		}
+	}
+
+	return nil
+}
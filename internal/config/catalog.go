@@ -0,0 +1,73 @@
+package config
+
+import "slices"
+
+// statefulResourceTypes is the built-in catalog of resource types, which hold data, secrets or identities. Removal of
+// such resources is hardly reversible, so they are suggested as critical ones by `init` command
+var statefulResourceTypes = []string{
+	// Azure
+	"azurerm_cosmosdb_account",
+	"azurerm_data_lake_store",
+	"azurerm_key_vault",
+	"azurerm_key_vault_certificate",
+	"azurerm_key_vault_key",
+	"azurerm_key_vault_secret",
+	"azurerm_kubernetes_cluster",
+	"azurerm_managed_disk",
+	"azurerm_mariadb_server",
+	"azurerm_mssql_database",
+	"azurerm_mssql_server",
+	"azurerm_mysql_flexible_server",
+	"azurerm_postgresql_flexible_server",
+	"azurerm_postgresql_server",
+	"azurerm_recovery_services_vault",
+	"azurerm_redis_cache",
+	"azurerm_resource_group",
+	"azurerm_servicebus_namespace",
+	"azurerm_storage_account",
+	"azurerm_storage_container",
+	"azurerm_storage_share",
+	"azurerm_user_assigned_identity",
+	// AWS
+	"aws_backup_vault",
+	"aws_db_instance",
+	"aws_dynamodb_table",
+	"aws_ebs_volume",
+	"aws_ecr_repository",
+	"aws_efs_file_system",
+	"aws_eks_cluster",
+	"aws_elasticache_cluster",
+	"aws_elasticache_replication_group",
+	"aws_kms_key",
+	"aws_rds_cluster",
+	"aws_route53_zone",
+	"aws_s3_bucket",
+	"aws_secretsmanager_secret",
+	"aws_sqs_queue",
+	// Google Cloud
+	"google_bigquery_dataset",
+	"google_bigquery_table",
+	"google_compute_disk",
+	"google_container_cluster",
+	"google_dns_managed_zone",
+	"google_kms_crypto_key",
+	"google_kms_key_ring",
+	"google_project",
+	"google_pubsub_topic",
+	"google_redis_instance",
+	"google_secret_manager_secret",
+	"google_spanner_database",
+	"google_sql_database",
+	"google_sql_database_instance",
+	"google_storage_bucket",
+	// Kubernetes
+	"kubernetes_namespace",
+	"kubernetes_persistent_volume",
+	"kubernetes_persistent_volume_claim",
+	"kubernetes_secret",
+}
+
+// IsStateful function checks if the resource type is in the built-in catalog of stateful resource types
+func IsStateful(resourceType string) bool {
+	return slices.Contains(statefulResourceTypes, resourceType)
+}
@@ -1,12 +1,66 @@
 package config
 
+import "time"
+
 type ConfigFile struct {
-	TfCmdBinaryFile    string   `mapstructure:"terraform_binary_file"`
-	TfPlanFileBasename string   `mapstructure:"terraform_plan_file_basename"`
-	SearchFolder       string   `mapstructure:"terraform_plan_search_folder"`
-	CriticalResources  []string `mapstructure:"critical_resources"`
-	AllowedRemovals    []string `mapstructure:"allowed_removals"`
-	NotUseTfChDirArg   bool     `mapstructure:"not_use_chdir"`
+	TfCmdBinaryFile    string         `mapstructure:"terraform_binary_file"`
+	TfPlanFileBasename string         `mapstructure:"terraform_plan_file_basename"`
+	SearchFolder       string         `mapstructure:"terraform_plan_search_folder"`
+	CriticalResources  []string       `mapstructure:"critical_resources"`
+	AllowedRemovals    []string       `mapstructure:"allowed_removals"`
+	NotUseTfChDirArg   bool           `mapstructure:"not_use_chdir"`
+	TemplatesDir       string         `mapstructure:"templates_dir"`
+	Outputs            []ReportOutput `mapstructure:"outputs"`
+	Notifications      []Notification `mapstructure:"notifications"`
+	Environments       []Environment  `mapstructure:"environments"`
+	BaselineFile       string         `mapstructure:"baseline_file"`
+	IgnoreRules        []IgnoreRule   `mapstructure:"ignore_rules"`
+}
+
+type ReportOutput struct {
+	Format   string `mapstructure:"format"`   //Name of report format, e.g. `github_markdown`, `html` or name of user supplied template
+	FileName string `mapstructure:"file"`     //Output file name of the report, "-" or empty string means Stdout
+	MaxSize  int    `mapstructure:"max_size"` //Max size of the report in characters, overrides the limit of the format (e.g. 65536 for GitHub comments)
+	Split    bool   `mapstructure:"split"`    //Whether to split the report exceeding the size limit to numbered part files instead of truncating
+}
+
+type Notification struct {
+	Type       string   `mapstructure:"type"`        //Type of the chat: `slack` or `teams`
+	WebhookUrl string   `mapstructure:"webhook_url"` //Incoming webhook URL, environment variables like ${SLACK_WEBHOOK_URL} are expanded
+	OnVerdicts []string `mapstructure:"on_verdicts"` //Verdicts to notify on: passed, warnings, blocked. Empty list means always
+}
+
+type Environment struct {
+	Name         string `mapstructure:"name"`          //Column name of the environment in matrix report
+	SearchFolder string `mapstructure:"search_folder"` //Common parent folder of plan files of the environment
+}
+
+// IgnoreRule is the list of noisy attributes of the resource type, whose changes are not taken into account
+type IgnoreRule struct {
+	ResourceType string   `mapstructure:"resource_type"` //Type of resources, `*` means any type
+	Attributes   []string `mapstructure:"attributes"`    //Paths of ignored attributes, including the nested ones
+}
+
+// Baseline is the file with known and accepted planned changes, e.g. perpetual diffs or removals approved ahead of time
+type Baseline struct {
+	Accepted []*AcceptedChange `mapstructure:"accepted"`
+}
+
+type AcceptedChange struct {
+	Address       string    `mapstructure:"address"`       //Address of the resource, e.g. `azurerm_key_vault.main`
+	Module        string    `mapstructure:"module"`        //Module of the resource, any module if empty
+	Action        string    `mapstructure:"action"`        //Planned action: create, update, delete or replace
+	Attribute     string    `mapstructure:"attribute"`     //Path of the changed attribute (including nested ones), any attribute if empty
+	Expires       time.Time `mapstructure:"expires"`       //Date after which the change is not accepted anymore, never expires if empty
+	Justification string    `mapstructure:"justification"` //Why the change is accepted
+}
+
+// Approval releases the removals of matching resources, e.g. approved with a label of pull request or by ChatOps step
+type Approval struct {
+	Address  string `mapstructure:"address"`  //Address of the resource, `*` matches any characters. Any address if empty
+	Module   string `mapstructure:"module"`   //Module of the resource, `*` matches any characters. Any module if empty
+	Approver string `mapstructure:"approver"` //Who has approved the removals
+	Reason   string `mapstructure:"reason"`   //Why the removals are approved
 }
 
 type DefensePlan struct {
@@ -14,11 +68,38 @@ type DefensePlan struct {
 	ExceptionalResources   map[string]bool //Depending on value IsAllCriticalSpecified this list (actually map) is `allowed for removal` (if true), or `critical for keeping` (if false)
 }
 
+// Override is the policy file `.tf-plan-reporter.yml` inside of module folder, which extends or tightens the policy of
+// the config file for the folder subtree
+type Override struct {
+	CriticalResources []string     `mapstructure:"critical_resources"` //Additionally critical resource types, or `all`
+	AllowedRemovals   []string     `mapstructure:"allowed_removals"`   //Additionally allowed for removal resource types
+	IgnoreRules       []IgnoreRule `mapstructure:"ignore_rules"`       //Appended to the ignore rules of the config file
+	FileName          string       `mapstructure:"-"`
+}
+
+// ModulePolicy is the effective policy of the module, with overrides of its folder and parent ones applied
+type ModulePolicy struct {
+	DefensePlan
+	IgnoreRules []IgnoreRule
+	Overrides   []string //Applied override files, starting from the root-most one
+}
+
 type AppConfig struct {
 	ConfigFile
 	ReportFileName         string
+	HtmlReportFileName     string
+	SplitReports           bool
+	GitHubActions          bool
+	StartedAt              time.Time
 	FailIfCriticalRemovals bool
 	FailIfNoTfPlanFound    bool
+	Approvals              []*Approval            //Approvals of removals supplied by `approvals` cli arg
+	Profile                string                 //Applied profile of the config file, if any
+	Sources                []string               //Config files in order of merging: included ones first
+	Locations              map[string]string      //Where the parameters are set last, e.g. `config.yml:12` or environment variable, by name
+	Effective              map[string]interface{} //Merged content of the config files with the profile applied
+	Overrides              map[string]*Override   //Per-directory overrides keyed by folder relative to the search folder
+	Baseline
 	DefensePlan
 }
 
@@ -21,6 +21,58 @@ allowed_removals:                             # Makes sense only if "all" item i
 # In short, if you're using terraform+terragrant bunch for cloud provisioning this parameter should be 'false'.
 # If you're using terraform only, it needs to be set up to 'true'
 not_use_chdir: false
+
+# Folder with report templates, which override built-in ones or add new report formats. OPTIONAL parameter
+# templates_dir: ./report-templates
+
+# Additional report outputs. The report is always printed to Stdout. OPTIONAL parameter
+# outputs:
+#   - format: html                            # Built-in formats: github_markdown, gitlab_markdown, azure_devops_markdown,
+#                                              # bitbucket_markdown, html, stdout. Or name of user supplied template
+#     file: report.html
+#   - format: gitlab_markdown
+#     file: note.md
+#     max_size: 100000                         # Max size of the report in characters, overrides the limit of the format
+#     split: false                              # Split the report exceeding the limit to numbered part files, instead of truncating
+
+# Chat notifications with summary and compact list of removals, posted to incoming webhooks. OPTIONAL parameter
+# notifications:
+#   - type: slack                               # slack or teams
+#     webhook_url: ${SLACK_WEBHOOK_URL}         # Environment variables are expanded
+#     on_verdicts:                              # Notify only on these verdicts: passed, warnings, blocked. Empty means always
+#       - blocked
+
+# Environments compared by 'matrix' mode, instead of 'terraform_plan_search_folder'. OPTIONAL parameter
+# environments:
+#   - name: dev
+#     search_folder: envs/dev
+#   - name: prod
+#     search_folder: envs/prod
+
+# Baseline file with known and accepted planned changes, e.g. perpetual diffs or removals approved ahead of time.
+# Might be overridden by '--baseline' CLI flag. OPTIONAL parameter
+# baseline_file: baseline.yml
+
+# Noisy attributes per resource type, which providers report as changed on every plan. The update of ignored attributes
+# only is reported as unchanged resource, and the amount of such updates is shown in the summary. OPTIONAL parameter
+# ignore_rules:
+#   - resource_type: "*"                       # Any resource type
+#     attributes:
+#       - tags_all
+#   - resource_type: azurerm_storage_account
+#     attributes:
+#       - etag
+#       - last_modified                         # Nested attributes are ignored as well, e.g. last_modified.time
+
+# Config files read before this one, which parameters are overridden by this file. OPTIONAL parameter
+# include:
+#   - common.yml
+
+# Named sets of parameters applied on top of this file with '--profile NAME' CLI flag. OPTIONAL parameter
+# profiles:
+#   prod:
+#     allowed_removals:
+#       - null_resource
 `
 
 func PrintExample() {
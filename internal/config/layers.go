@@ -0,0 +1,329 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// OverrideFileName is the base name of per-directory policy files
+	OverrideFileName = ".tf-plan-reporter.yml"
+
+	includeKey  = "include"
+	profilesKey = "profiles"
+)
+
+// readLayers function reads the config file into the viper instance. The files listed in `include` parameter (relative
+// to the including file) are read first, so the including file overrides their parameters. Lists are replaced, not
+// appended. The content is checked by the schema of config file, and the locations of its parameters are recorded.
+// Returns read files in order of merging
+func readLayers(viper_runtime *viper.Viper, name string, chain []string, locations map[string]string) ([]string, error) {
+	absName, err := filepath.Abs(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(chain, absName) {
+		return nil, fmt.Errorf("config files include each other: %s -> %s", strings.Join(chain, " -> "), absName)
+	}
+
+	document, err := readNode(name, configSchema())
+	if err != nil {
+		return nil, err
+	}
+
+	file_runtime := viper.New()
+
+	file_runtime.SetConfigType("yaml")
+	file_runtime.SetConfigFile(name)
+
+	if err := file_runtime.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var sources []string
+	for _, include := range file_runtime.GetStringSlice(includeKey) {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(name), include)
+		}
+
+		included, err := readLayers(viper_runtime, include, append(chain, absName), locations)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, included...)
+	}
+
+	settings := file_runtime.AllSettings()
+	delete(settings, includeKey)
+
+	if err := viper_runtime.MergeConfigMap(settings); err != nil {
+		return nil, err
+	}
+
+	recordLocations(locations, name, document, "")
+
+	log.WithField("config_file", name).Debug("Config file has been merged")
+
+	return append(sources, name), nil
+}
+
+// recordLocations function records the locations of the parameters set by the YAML document in form `file:line`, keyed by
+// dotted path of the parameter. The parameters of profiles are recorded as well, e.g. `profiles.prod.allowed_removals`
+func recordLocations(locations map[string]string, fileName string, node *yaml.Node, path string) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := joinPath(path, strings.ToLower(node.Content[i].Value))
+		locations[key] = fmt.Sprintf("%s:%d", fileName, node.Content[i].Line)
+
+		if key == profilesKey || path == profilesKey {
+			recordLocations(locations, fileName, node.Content[i+1], key)
+		}
+	}
+}
+
+// applyProfile function merges the parameters of the profile from `profiles` section on top of the config
+func applyProfile(viper_runtime *viper.Viper, profile string, locations map[string]string) error {
+	key := profilesKey + "." + strings.ToLower(profile)
+	if !viper_runtime.IsSet(key) {
+		return fmt.Errorf("profile '%s' is not found in '%s' section", profile, profilesKey)
+	}
+
+	log.WithField("profile", profile).Debug("Applying of config profile")
+
+	for name, location := range locations {
+		if parameter, found := strings.CutPrefix(name, key+"."); found {
+			locations[parameter] = location
+		}
+	}
+
+	return viper_runtime.MergeConfigMap(viper_runtime.GetStringMap(key))
+}
+
+// LoadOverrides function loads per-directory policy files found in the search folder, keyed by their folders relative
+// to the search folder
+func (c *AppConfig) LoadOverrides() error {
+	c.Overrides = make(map[string]*Override)
+
+	fileNames, err := FindOverrides(c.SearchFolder)
+	if err != nil {
+		return err
+	}
+
+	for _, fileName := range fileNames {
+		override, err := loadOverride(fileName)
+		if err != nil {
+			return err
+		}
+
+		folder, err := filepath.Rel(c.SearchFolder, filepath.Dir(fileName))
+		if err != nil {
+			return err
+		}
+		c.Overrides[folder] = override
+
+		log.WithFields(log.Fields{
+			"override_file": fileName,
+			"folder":        folder,
+		}).Debug("Per-directory override has been loaded")
+	}
+
+	return nil
+}
+
+// ForSearchFolder function returns the copy of the config searching plans in the other folder, e.g. the one of matrix
+// environment. Its overrides are loaded from that folder, because module names are relative to it
+func (c *AppConfig) ForSearchFolder(folder string) (*AppConfig, error) {
+	copied := *c
+	copied.SearchFolder = folder
+
+	if err := copied.LoadOverrides(); err != nil {
+		return nil, err
+	}
+
+	return &copied, nil
+}
+
+// FindOverrides function returns per-directory policy files found in the folder and its subfolders. Terragrunt cache
+// and terraform folders are skipped. Nothing is found, if the folder is not specified or doesn't exist
+func FindOverrides(folder string) ([]string, error) {
+	if len(folder) == 0 {
+		return nil, nil
+	}
+
+	if stat, err := os.Stat(folder); err != nil || !stat.IsDir() {
+		return nil, nil //The search folder is checked by the validator, if it's needed at all
+	}
+
+	var result []string
+	err := filepath.WalkDir(folder, func(currentPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			switch d.Name() {
+			case ".terragrunt-cache", ".terraform", ".git":
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.Name() == OverrideFileName {
+			result = append(result, currentPath)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+func loadOverride(fileName string) (*Override, error) {
+	if _, err := readNode(fileName, overrideSchema()); err != nil {
+		return nil, err
+	}
+
+	viper_runtime := viper.New()
+
+	viper_runtime.SetConfigType("yaml")
+	viper_runtime.SetConfigFile(fileName)
+
+	if err := viper_runtime.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	override := &Override{FileName: fileName}
+	if err := viper_runtime.Unmarshal(override); err != nil {
+		return nil, fmt.Errorf("could not parse override file %s: %w", fileName, err)
+	}
+
+	for _, rule := range override.IgnoreRules {
+		if len(rule.ResourceType) == 0 || len(rule.Attributes) == 0 {
+			return nil, fmt.Errorf("override file %s: ignore rule must have both 'resource_type' and 'attributes'", fileName)
+		}
+	}
+
+	return override, nil
+}
+
+// SourceFiles function returns all files the config is loaded from: the config files with included ones, per-directory
+// overrides and the baseline file, e.g. to reload the config when any of them is changed
+func (c *AppConfig) SourceFiles() []string {
+	files := slices.Clone(c.Sources)
+
+	var overrides []string
+	for _, override := range c.Overrides {
+		overrides = append(overrides, override.FileName)
+	}
+	slices.Sort(overrides)
+	files = append(files, overrides...)
+
+	if len(c.BaselineFile) > 0 {
+		files = append(files, c.BaselineFile)
+	}
+
+	return files
+}
+
+// PolicyFor function returns the effective policy of the module, whose name is the folder relative to the search folder.
+// Overrides are applied from the root-most folder to the module one
+func (c *AppConfig) PolicyFor(module string) *ModulePolicy {
+	policy := &ModulePolicy{
+		DefensePlan: DefensePlan{
+			IsAllCriticalSpecified: c.IsAllCriticalSpecified,
+			ExceptionalResources:   maps.Clone(c.ExceptionalResources),
+		},
+		IgnoreRules: slices.Clone(c.IgnoreRules),
+	}
+	if policy.ExceptionalResources == nil {
+		policy.ExceptionalResources = make(map[string]bool)
+	}
+
+	for _, folder := range folderChain(module) {
+		override, ok := c.Overrides[folder]
+		if !ok {
+			continue
+		}
+
+		override.apply(&policy.DefensePlan)
+		policy.IgnoreRules = append(policy.IgnoreRules, override.IgnoreRules...)
+		policy.Overrides = append(policy.Overrides, override.FileName)
+	}
+
+	return policy
+}
+
+// IsAllowed function checks if the resource type is allowed for removal by the policy
+func (dp *DefensePlan) IsAllowed(resourceType string) bool {
+	return dp.ExceptionalResources[resourceType] == dp.IsAllCriticalSpecified
+}
+
+// apply function extends or tightens the policy: critical resource types become forbidden for removal and allowed ones
+// become allowed. If `all` is in critical resources, the policy of the subtree is replaced by the override one
+func (o *Override) apply(plan *DefensePlan) {
+	if slices.ContainsFunc(o.CriticalResources, isAll) {
+		plan.IsAllCriticalSpecified = true
+		plan.ExceptionalResources = make(map[string]bool)
+		for _, item := range o.AllowedRemovals {
+			plan.ExceptionalResources[strings.ToLower(item)] = true
+		}
+
+		return
+	}
+
+	for _, item := range o.CriticalResources {
+		if plan.IsAllCriticalSpecified {
+			delete(plan.ExceptionalResources, strings.ToLower(item))
+		} else {
+			plan.ExceptionalResources[strings.ToLower(item)] = true
+		}
+	}
+
+	for _, item := range o.AllowedRemovals {
+		if plan.IsAllCriticalSpecified {
+			plan.ExceptionalResources[strings.ToLower(item)] = true
+		} else {
+			delete(plan.ExceptionalResources, strings.ToLower(item))
+		}
+	}
+}
+
+func isAll(item string) bool {
+	return strings.TrimSpace(strings.ToLower(item)) == "all"
+}
+
+// folderChain function returns the module folder and all its parents, starting from the root one `.`
+func folderChain(module string) []string {
+	chain := []string{"."}
+
+	module = filepath.Clean(module)
+	if module == "." {
+		return chain
+	}
+
+	parts := strings.Split(module, string(os.PathSeparator))
+	for i := range parts {
+		chain = append(chain, filepath.Join(parts[:i+1]...))
+	}
+
+	return chain
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LayersTestSuite struct {
+	suite.Suite
+	tmpDir string
+}
+
+func (ts *LayersTestSuite) SetupTest() {
+	ts.tmpDir = ts.T().TempDir()
+}
+
+func (ts *LayersTestSuite) createFile(name string, content string) string {
+	fileName := path.Join(ts.tmpDir, name)
+
+	if err := os.MkdirAll(path.Dir(fileName), 0755); err != nil {
+		ts.T().Fatalf("Could not create folder of file: %s", fileName) //nolint:typecheck
+	}
+
+	if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+		ts.T().Fatalf("Could not write file content: %s", fileName) //nolint:typecheck
+	}
+
+	return fileName
+}
+
+func (ts *LayersTestSuite) TestIncludeAndProfile() {
+	ts.createFile("common.yml", `
+terraform_binary_file: /usr/bin/terraform
+terraform_plan_file_basename: plan.bin
+critical_resources: [all]
+allowed_removals: [null_resource]
+`)
+	fileName := ts.createFile("config.yml", `
+include: [common.yml]
+terraform_plan_file_basename: tfplan.bin
+profiles:
+  prod:
+    allowed_removals: [azurerm_role_assignment]
+`)
+
+	settings, err := LoadWithOptions(fileName, Options{Profile: "prod"})
+
+	assert.NoError(ts.T(), err)                                                                           //nolint:typecheck
+	assert.Equal(ts.T(), "/usr/bin/terraform", settings.TfCmdBinaryFile)                                  //nolint:typecheck
+	assert.Equal(ts.T(), "tfplan.bin", settings.TfPlanFileBasename)                                       //nolint:typecheck
+	assert.Equal(ts.T(), []string{"azurerm_role_assignment"}, settings.AllowedRemovals)                   //nolint:typecheck
+	assert.Equal(ts.T(), map[string]bool{"azurerm_role_assignment": true}, settings.ExceptionalResources) //nolint:typecheck
+	assert.Equal(ts.T(), 2, len(settings.Sources))                                                        //nolint:typecheck
+	assert.NotContains(ts.T(), settings.Effective, "profiles")                                            //nolint:typecheck
+}
+
+func (ts *LayersTestSuite) TestUnknownProfile() {
+	fileName := ts.createFile("config.yml", "critical_resources: [all]\n")
+
+	_, err := LoadWithOptions(fileName, Options{Profile: "stage"})
+
+	assert.ErrorContains(ts.T(), err, "profile 'stage' is not found") //nolint:typecheck
+}
+
+func (ts *LayersTestSuite) TestIncludeCycle() {
+	ts.createFile("a.yml", "include: [b.yml]\n")
+	fileName := ts.createFile("b.yml", "include: [a.yml]\n")
+
+	_, err := Load(fileName)
+
+	assert.ErrorContains(ts.T(), err, "config files include each other") //nolint:typecheck
+}
+
+func (ts *LayersTestSuite) TestOverrides() {
+	ts.createFile("key-vaults/"+OverrideFileName, "critical_resources: [azurerm_key_vault_secret]\n")
+	ts.createFile("key-vaults/legacy/"+OverrideFileName, "allowed_removals: [azurerm_key_vault]\n")
+	ts.createFile("sandbox/"+OverrideFileName, "critical_resources: [all]\n")
+	ts.createFile("key-vaults/.terragrunt-cache/"+OverrideFileName, "allowed_removals: [azurerm_key_vault_secret]\n")
+	fileName := ts.createFile("config.yml", `
+terraform_plan_search_folder: `+ts.tmpDir+`
+critical_resources: [azurerm_key_vault]
+`)
+
+	settings, err := Load(fileName)
+	assert.NoError(ts.T(), err)                      //nolint:typecheck
+	assert.Equal(ts.T(), 3, len(settings.Overrides)) //nolint:typecheck
+
+	root := settings.PolicyFor("rgs")
+	assert.False(ts.T(), root.IsAllowed("azurerm_key_vault"))       //nolint:typecheck
+	assert.True(ts.T(), root.IsAllowed("azurerm_key_vault_secret")) //nolint:typecheck
+
+	keyVaults := settings.PolicyFor("key-vaults/main")
+	assert.False(ts.T(), keyVaults.IsAllowed("azurerm_key_vault_secret")) //nolint:typecheck
+	assert.Equal(ts.T(), 1, len(keyVaults.Overrides))                     //nolint:typecheck
+
+	legacy := settings.PolicyFor("key-vaults/legacy")
+	assert.True(ts.T(), legacy.IsAllowed("azurerm_key_vault"))         //nolint:typecheck
+	assert.False(ts.T(), legacy.IsAllowed("azurerm_key_vault_secret")) //nolint:typecheck
+
+	sandbox := settings.PolicyFor("sandbox")
+	assert.False(ts.T(), sandbox.IsAllowed("null_resource")) //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestLayers(t *testing.T) {
+	suite.Run(t, new(LayersTestSuite))
+}
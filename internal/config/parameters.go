@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvPrefix is the prefix of environment variables overriding config file parameters, e.g.
+	// `TFPR_TERRAFORM_PLAN_SEARCH_FOLDER` overrides `terraform_plan_search_folder`
+	EnvPrefix = "TFPR_"
+)
+
+// Options of config loading
+type Options struct {
+	Profile    string            //Profile applied on top of the config file, if not empty
+	Parameters map[string]string //Config file parameters by name, e.g. from cli flags. They take precedence over all other layers
+}
+
+// Parameter is the config file parameter, which might be overridden by environment variable or cli flag
+type Parameter struct {
+	Name       string //Name in the config file, e.g. `critical_resources`
+	Structured bool   //The value is a list of objects, it's given as YAML (or JSON) string, e.g. `[{format: html, file: r.html}]`
+	List       bool   //The value is a list of strings, it's given as comma separated string
+	Bool       bool   //The value is either true or false
+}
+
+// EnvName function returns the name of environment variable overriding the parameter
+func (p Parameter) EnvName() string {
+	return EnvPrefix + strings.ToUpper(p.Name)
+}
+
+// FlagName function returns the name of cli flag overriding the parameter, e.g. `critical-resources`
+func (p Parameter) FlagName() string {
+	return strings.ReplaceAll(p.Name, "_", "-")
+}
+
+// Parameters function returns all parameters of the config file
+func Parameters() []Parameter {
+	var parameters []Parameter
+
+	configType := reflect.TypeOf(ConfigFile{})
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+
+		parameter := Parameter{Name: field.Tag.Get("mapstructure"), Bool: field.Type.Kind() == reflect.Bool}
+		if field.Type.Kind() == reflect.Slice {
+			if field.Type.Elem().Kind() == reflect.String {
+				parameter.List = true
+			} else {
+				parameter.Structured = true
+			}
+		}
+
+		parameters = append(parameters, parameter)
+	}
+
+	return parameters
+}
+
+// IsSetByEnvironment function checks if any config file parameter is set by environment variable
+func IsSetByEnvironment() bool {
+	for _, parameter := range Parameters() {
+		if _, ok := os.LookupEnv(parameter.EnvName()); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyEnvironment function overrides config file parameters by `TFPR_*` environment variables
+func applyEnvironment(viper_runtime *viper.Viper, locations map[string]string) error {
+	for _, parameter := range Parameters() {
+		value, ok := os.LookupEnv(parameter.EnvName())
+		if !ok {
+			continue
+		}
+
+		if err := setParameter(viper_runtime, parameter.Name, value); err != nil {
+			return fmt.Errorf("environment variable %s: %w", parameter.EnvName(), err)
+		}
+		locations[parameter.Name] = parameter.EnvName() + " environment variable"
+
+		log.WithField("parameter", parameter.Name).Debugf("Config file parameter is overridden by %s environment variable", parameter.EnvName())
+	}
+
+	return nil
+}
+
+// setParameter function overrides the config file parameter by its string value. Lists of strings are comma separated,
+// lists of objects are YAML (or JSON, as its subset) documents
+func setParameter(viper_runtime *viper.Viper, name string, value string) error {
+	for _, parameter := range Parameters() {
+		if parameter.Name != name {
+			continue
+		}
+
+		switch {
+		case parameter.Structured:
+			var document yaml.Node
+			if err := yaml.Unmarshal([]byte(value), &document); err != nil {
+				return fmt.Errorf("value must be YAML or JSON list: %w", err)
+			}
+			if err := parameterSchema(name).check("", &document, name); err != nil {
+				return err
+			}
+
+			var parsed []interface{}
+			if err := document.Decode(&parsed); err != nil {
+				return fmt.Errorf("value must be YAML or JSON list: %w", err)
+			}
+			viper_runtime.Set(name, parsed)
+		case parameter.List:
+			var items []string
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); len(item) > 0 {
+					items = append(items, item)
+				}
+			}
+			viper_runtime.Set(name, items)
+		default:
+			viper_runtime.Set(name, value)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown config file parameter '%s'", name)
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ParametersTestSuite struct {
+	suite.Suite
+	fileName string
+}
+
+func (ts *ParametersTestSuite) SetupTest() {
+	ts.fileName = path.Join(ts.T().TempDir(), "config.yml")
+
+	content := `
+terraform_binary_file: /usr/bin/terraform
+terraform_plan_file_basename: plan.bin
+critical_resources: [all]
+profiles:
+  prod:
+    allowed_removals: [azurerm_role_assignment]
+`
+	if err := os.WriteFile(ts.fileName, []byte(content), 0644); err != nil {
+		ts.T().Fatalf("Could not write file content: %s", ts.fileName) //nolint:typecheck
+	}
+}
+
+func (ts *ParametersTestSuite) TestEnvironmentOverridesProfile() {
+	ts.T().Setenv("TFPR_ALLOWED_REMOVALS", "null_resource, time_sleep")
+	ts.T().Setenv("TFPR_NOT_USE_CHDIR", "true")
+
+	settings, err := LoadWithOptions(ts.fileName, Options{Profile: "prod"})
+
+	assert.NoError(ts.T(), err)                                                                                     //nolint:typecheck
+	assert.Equal(ts.T(), []string{"null_resource", "time_sleep"}, settings.AllowedRemovals)                         //nolint:typecheck
+	assert.Equal(ts.T(), true, settings.NotUseTfChDirArg)                                                           //nolint:typecheck
+	assert.Equal(ts.T(), "plan.bin", settings.TfPlanFileBasename)                                                   //nolint:typecheck
+	assert.Equal(ts.T(), map[string]bool{"null_resource": true, "time_sleep": true}, settings.ExceptionalResources) //nolint:typecheck
+}
+
+func (ts *ParametersTestSuite) TestParametersOverrideEnvironment() {
+	ts.T().Setenv("TFPR_TERRAFORM_PLAN_FILE_BASENAME", "env.bin")
+
+	settings, err := LoadWithOptions(ts.fileName, Options{Parameters: map[string]string{
+		"terraform_plan_file_basename": "flag.bin",
+		"ignore_rules":                 `[{resource_type: azurerm_key_vault, attributes: [tags]}]`,
+	}})
+
+	assert.NoError(ts.T(), err)                                                                                                 //nolint:typecheck
+	assert.Equal(ts.T(), "flag.bin", settings.TfPlanFileBasename)                                                               //nolint:typecheck
+	assert.Equal(ts.T(), []IgnoreRule{{ResourceType: "azurerm_key_vault", Attributes: []string{"tags"}}}, settings.IgnoreRules) //nolint:typecheck
+}
+
+func (ts *ParametersTestSuite) TestWithoutConfigFile() {
+	ts.T().Setenv("TFPR_TERRAFORM_BINARY_FILE", "/usr/bin/terraform")
+	ts.T().Setenv("TFPR_CRITICAL_RESOURCES", "all")
+
+	settings, err := LoadWithOptions("", Options{})
+
+	assert.NoError(ts.T(), err)                                          //nolint:typecheck
+	assert.Equal(ts.T(), "/usr/bin/terraform", settings.TfCmdBinaryFile) //nolint:typecheck
+	assert.Equal(ts.T(), []string{"all"}, settings.CriticalResources)    //nolint:typecheck
+	assert.Empty(ts.T(), settings.Sources)                               //nolint:typecheck
+	assert.True(ts.T(), IsSetByEnvironment())                            //nolint:typecheck
+}
+
+func (ts *ParametersTestSuite) TestMalformedStructuredParameter() {
+	_, err := LoadWithOptions(ts.fileName, Options{Parameters: map[string]string{"ignore_rules": "{resource_type"}})
+
+	assert.ErrorContains(ts.T(), err, "parameter 'ignore_rules'") //nolint:typecheck
+}
+
+func (ts *ParametersTestSuite) TestUnknownParameter() {
+	_, err := LoadWithOptions(ts.fileName, Options{Parameters: map[string]string{"critical_resource": "all"}})
+
+	assert.ErrorContains(ts.T(), err, "unknown config file parameter 'critical_resource'") //nolint:typecheck
+}
+
+func TestParameters(t *testing.T) {
+	suite.Run(t, new(ParametersTestSuite))
+}
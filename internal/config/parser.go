@@ -1,35 +1,70 @@
 package config
 
 import (
+	"fmt"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/mitchellh/mapstructure"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
-func Parse(name string) *AppConfig {
+// Load function reads the config file together with its included files and `TFPR_*` environment variables applied
+func Load(name string) (*AppConfig, error) {
+	return LoadWithOptions(name, Options{})
+}
+
+// LoadWithOptions function reads the config file together with its included files and applies the layers on top of it
+// in order of precedence: the profile (if any), `TFPR_*` environment variables and parameters of the options. The config
+// file name might be empty, if all parameters are given by the environment variables and parameters. Then per-directory
+// overrides found in the search folder are loaded
+func LoadWithOptions(name string, options Options) (*AppConfig, error) {
 	viper_runtime := viper.New()
 
-	viper_runtime.SetConfigType("yaml")
-	viper_runtime.SetConfigFile(name)
+	locations := make(map[string]string)
 
-	if err := viper_runtime.ReadInConfig(); err != nil {
-		log.Fatal(err)
+	var sources []string
+	if len(name) > 0 {
+		var err error
+		if sources, err = readLayers(viper_runtime, name, nil, locations); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(options.Profile) > 0 {
+		if err := applyProfile(viper_runtime, options.Profile, locations); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	if err := applyEnvironment(viper_runtime, locations); err != nil {
+		return nil, err
+	}
+
+	for key, value := range options.Parameters {
+		if err := setParameter(viper_runtime, key, value); err != nil {
+			return nil, fmt.Errorf("parameter '%s': %w", key, err)
+		}
+		locations[key] = "command line"
 	}
 
 	appConfig := create()
 	var configFile ConfigFile
 	if err := viper_runtime.Unmarshal(&configFile); err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	log.Debugf("Content of config file/structure: %v", configFile)
 	appConfig.ConfigFile = configFile
+	appConfig.Profile = options.Profile
+	appConfig.Sources = sources
+	appConfig.Locations = locations
+	appConfig.Effective = viper_runtime.AllSettings()
+	delete(appConfig.Effective, profilesKey)
 
-	if i := slices.IndexFunc(appConfig.ConfigFile.CriticalResources, func(s string) bool {
-		return strings.TrimSpace(strings.ToLower(s)) == "all"
-	}); i > -1 {
+	if slices.ContainsFunc(appConfig.ConfigFile.CriticalResources, isAll) {
 		appConfig.IsAllCriticalSpecified = true
 		for _, item := range appConfig.AllowedRemovals {
 			appConfig.ExceptionalResources[strings.ToLower(item)] = true //here it plays role of `allowed for removals` resources
@@ -41,5 +76,96 @@ func Parse(name string) *AppConfig {
 		}
 	}
 
-	return appConfig
+	if err := appConfig.LoadOverrides(); err != nil {
+		return nil, err
+	}
+
+	if err := appConfig.LoadBaseline(); err != nil {
+		return nil, err
+	}
+
+	return appConfig, nil
+}
+
+// LoadBaseline function reads the baseline file specified by `baseline_file` parameter, if any. Expiry dates are
+// expected in YYYY-MM-DD format
+func (c *AppConfig) LoadBaseline() error {
+	c.Baseline = Baseline{}
+
+	if len(c.BaselineFile) == 0 {
+		return nil
+	}
+
+	if _, err := readNode(c.BaselineFile, baselineSchema()); err != nil {
+		return err
+	}
+
+	viper_runtime := viper.New()
+
+	viper_runtime.SetConfigType("yaml")
+	viper_runtime.SetConfigFile(c.BaselineFile)
+
+	if err := viper_runtime.ReadInConfig(); err != nil {
+		return err
+	}
+
+	if err := viper_runtime.Unmarshal(&c.Baseline, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeHookFunc(time.DateOnly),
+		mapstructure.StringToSliceHookFunc(","),
+	))); err != nil {
+		return fmt.Errorf("could not parse baseline file %s: %w", c.BaselineFile, err)
+	}
+
+	for i, accepted := range c.Accepted {
+		if len(accepted.Address) == 0 || len(accepted.Action) == 0 {
+			return fmt.Errorf("baseline file %s: entry %d must have both 'address' and 'action'", c.BaselineFile, i+1)
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"baseline_file":  c.BaselineFile,
+		"accepted_total": len(c.Accepted),
+	}).Debug("Baseline file has been loaded")
+
+	return nil
+}
+
+// LoadApprovals function reads the approvals file. Every approval must have the approver and either address
+// or module pattern, so that a single approval could not release all removals by mistake
+func LoadApprovals(fileName string) ([]*Approval, error) {
+	if _, err := readNode(fileName, approvalsSchema()); err != nil {
+		return nil, err
+	}
+
+	viper_runtime := viper.New()
+
+	viper_runtime.SetConfigType("yaml")
+	viper_runtime.SetConfigFile(fileName)
+
+	if err := viper_runtime.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var approvals struct {
+		Approvals []*Approval `mapstructure:"approvals"`
+	}
+	if err := viper_runtime.Unmarshal(&approvals); err != nil {
+		return nil, fmt.Errorf("could not parse approvals file %s: %w", fileName, err)
+	}
+
+	for i, approval := range approvals.Approvals {
+		if len(approval.Approver) == 0 {
+			return nil, fmt.Errorf("approvals file %s: approval %d must have 'approver'", fileName, i+1)
+		}
+		if len(approval.Address) == 0 && len(approval.Module) == 0 {
+			return nil, fmt.Errorf("approvals file %s: approval %d must have either 'address' or 'module'", fileName, i+1)
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"approvals_file":  fileName,
+		"approvals_total": len(approvals.Approvals),
+	}).Debug("Approvals file has been loaded")
+
+	return approvals.Approvals, nil
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"path"
 	"testing"
+	"time"
 
 	"os"
 
@@ -47,7 +48,8 @@ terraform_plan_search_folder: ./
 
 	ts.createFile(fileName, fileContent)
 
-	parsedConfig := Parse(fileName)
+	parsedConfig, err := Load(fileName)
+	assert.NoError(ts.T(), err) //nolint:typecheck
 
 	assert.Equal(ts.T(), "/usr/local/bin/terraform", parsedConfig.TfCmdBinaryFile) //nolint:typecheck
 	assert.Equal(ts.T(), "tfplan.bin", parsedConfig.TfPlanFileBasename)            //nolint:typecheck
@@ -67,7 +69,8 @@ func (ts *ConfigParserTestSuite) TestParsingEmptyFile() {
 
 	ts.createFile(fileName, fileContent)
 
-	parsedConfig := Parse(fileName)
+	parsedConfig, err := Load(fileName)
+	assert.NoError(ts.T(), err) //nolint:typecheck
 
 	assert.Equal(ts.T(), "", parsedConfig.TfCmdBinaryFile)              //nolint:typecheck
 	assert.Equal(ts.T(), "", parsedConfig.TfPlanFileBasename)           //nolint:typecheck
@@ -101,7 +104,8 @@ allowed_removals:  # makes sense only if "all" specified in "critical_resources"
 
 	ts.createFile(fileName, fileContent)
 
-	parsedConfig := Parse(fileName)
+	parsedConfig, err := Load(fileName)
+	assert.NoError(ts.T(), err) //nolint:typecheck
 
 	assert.Equal(ts.T(), "./test-plan-reader", parsedConfig.TfCmdBinaryFile)                                           //nolint:typecheck
 	assert.Equal(ts.T(), "plan.json", parsedConfig.TfPlanFileBasename)                                                 //nolint:typecheck
@@ -114,6 +118,79 @@ allowed_removals:  # makes sense only if "all" specified in "critical_resources"
 
 }
 
+func (ts *ConfigParserTestSuite) TestParsingBaselineFile() {
+	baselineFileName := path.Join(ts.tmpDir, "baseline.yaml")
+	ts.createFile(baselineFileName, `
+accepted:
+  - address: azurerm_key_vault.legacy
+    module: core/key-vaults
+    action: delete
+    expires: 2026-12-31
+    justification: Migrated to the shared key vault
+  - address: azurerm_storage_account.logs
+    action: update
+    attribute: network_rules
+`)
+
+	fileName := path.Join(ts.tmpDir, "config_baseline.yaml")
+	ts.createFile(fileName, `
+terraform_binary_file: ./test-plan-reader
+terraform_plan_file_basename: plan.json
+terraform_plan_search_folder: /tmp
+baseline_file: `+baselineFileName+`
+`)
+
+	parsedConfig, err := Load(fileName)
+
+	assert.NoError(ts.T(), err)                                                                      //nolint:typecheck
+	assert.Equal(ts.T(), 2, len(parsedConfig.Accepted))                                              //nolint:typecheck
+	assert.Equal(ts.T(), "core/key-vaults", parsedConfig.Accepted[0].Module)                         //nolint:typecheck
+	assert.Equal(ts.T(), "2026-12-31", parsedConfig.Accepted[0].Expires.Format(time.DateOnly))       //nolint:typecheck
+	assert.Equal(ts.T(), "Migrated to the shared key vault", parsedConfig.Accepted[0].Justification) //nolint:typecheck
+	assert.Equal(ts.T(), "network_rules", parsedConfig.Accepted[1].Attribute)                        //nolint:typecheck
+	assert.True(ts.T(), parsedConfig.Accepted[1].Expires.IsZero())                                   //nolint:typecheck
+}
+
+func (ts *ConfigParserTestSuite) TestParsingBaselineFileWithoutAction() {
+	parsedConfig := &AppConfig{ConfigFile: ConfigFile{BaselineFile: path.Join(ts.tmpDir, "baseline_invalid.yaml")}}
+	ts.createFile(parsedConfig.BaselineFile, `
+accepted:
+  - address: azurerm_key_vault.legacy
+`)
+
+	assert.ErrorContains(ts.T(), parsedConfig.LoadBaseline(), "must have both 'address' and 'action'") //nolint:typecheck
+}
+
+func (ts *ConfigParserTestSuite) TestParsingApprovalsFile() {
+	fileName := path.Join(ts.tmpDir, "approvals.yaml")
+	ts.createFile(fileName, `
+approvals:
+  - address: azurerm_key_vault.main
+    approver: alice
+    reason: Approved in CHG-1234
+  - module: legacy/*
+    approver: bob
+`)
+
+	approvals, err := LoadApprovals(fileName)
+
+	assert.NoError(ts.T(), err)                                                                                                         //nolint:typecheck
+	assert.Equal(ts.T(), &Approval{Address: "azurerm_key_vault.main", Approver: "alice", Reason: "Approved in CHG-1234"}, approvals[0]) //nolint:typecheck
+	assert.Equal(ts.T(), &Approval{Module: "legacy/*", Approver: "bob"}, approvals[1])                                                  //nolint:typecheck
+}
+
+func (ts *ConfigParserTestSuite) TestParsingApprovalsFileWithoutPattern() {
+	fileName := path.Join(ts.tmpDir, "approvals_invalid.yaml")
+	ts.createFile(fileName, `
+approvals:
+  - approver: alice
+`)
+
+	_, err := LoadApprovals(fileName)
+
+	assert.ErrorContains(ts.T(), err, "must have either 'address' or 'module'") //nolint:typecheck
+}
+
 // Entry point for the test suite
 func TestConfigParsingDefault(t *testing.T) {
 	suite.Run(t, new(ConfigParserTestSuite))
@@ -0,0 +1,345 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	schemaObject  = "object"
+	schemaMap     = "map" //Object with arbitrary keys, e.g. `profiles`
+	schemaArray   = "array"
+	schemaString  = "string"
+	schemaBoolean = "boolean"
+	schemaInteger = "integer"
+	schemaDate    = "date"
+)
+
+// parameterDescriptions are shown by editors with help of the published JSON Schema
+var parameterDescriptions = map[string]string{
+	"terraform_binary_file":        "Path to terraform binary file, absolute or relative to current working directory",
+	"terraform_plan_file_basename": "Base name of terraform generated binary plan files, which are searched for",
+	"terraform_plan_search_folder": "Folder where the plan files are searched for recursively",
+	"critical_resources":           "Resource types which should be kept from accidental removals, or 'all' and then 'allowed_removals' is taken into account",
+	"allowed_removals":             "Resource types allowed for removal, if 'critical_resources' is 'all'",
+	"not_use_chdir":                "Whether the '-chdir=DIR' arg of terraform command is not used, e.g. for terraform without terragrunt",
+	"templates_dir":                "Folder with report templates, which override built-in ones or add new report formats",
+	"outputs":                      "Additional report outputs. The report is always printed to Stdout",
+	"notifications":                "Chat notifications with summary and compact list of removals, posted to incoming webhooks",
+	"environments":                 "Environments compared by 'matrix' mode, instead of 'terraform_plan_search_folder'",
+	"baseline_file":                "Baseline file with known and accepted planned changes",
+	"ignore_rules":                 "Noisy attributes per resource type, whose updates are reported as unchanged resources",
+	includeKey:                     "Config files read before this one, relative to it. This file overrides their parameters",
+	profilesKey:                    "Named sets of parameters applied on top of this file with '--profile NAME' cli flag",
+}
+
+// SchemaError is the problem of config content found by its schema, with the location of the problem
+type SchemaError struct {
+	FileName string //Empty, if the content is not read from file, e.g. from environment variable
+	Line     int
+	Column   int
+	Message  string
+}
+
+func (e *SchemaError) Error() string {
+	if len(e.FileName) == 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s", e.FileName, e.Line, e.Column, e.Message)
+}
+
+// schema describes the expected structure of YAML content. It's built from mapstructure tags of config structures,
+// so that it never diverges from the parser
+type schema struct {
+	kind        string
+	properties  map[string]*schema //Keys of the object
+	items       *schema            //Items of the array or values of the map
+	description string
+}
+
+// schemaOf function builds the schema of config structure type
+func schemaOf(t reflect.Type) *schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem())
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return &schema{kind: schemaDate}
+		}
+
+		s := &schema{kind: schemaObject, properties: make(map[string]*schema)}
+		for i := 0; i < t.NumField(); i++ {
+			name := t.Field(i).Tag.Get("mapstructure")
+			if len(name) == 0 || name == "-" {
+				continue
+			}
+
+			s.properties[name] = schemaOf(t.Field(i).Type)
+		}
+
+		return s
+	case reflect.Slice:
+		return &schema{kind: schemaArray, items: schemaOf(t.Elem())}
+	case reflect.Map:
+		return &schema{kind: schemaMap, items: schemaOf(t.Elem())}
+	case reflect.Bool:
+		return &schema{kind: schemaBoolean}
+	case reflect.Int, reflect.Int64:
+		return &schema{kind: schemaInteger}
+	default:
+		return &schema{kind: schemaString}
+	}
+}
+
+// configSchema function returns the schema of the config file, including `include` and `profiles` sections
+func configSchema() *schema {
+	s := parametersSchema()
+	s.properties[profilesKey] = &schema{kind: schemaMap, items: parametersSchema(), description: parameterDescriptions[profilesKey]}
+	s.properties[includeKey] = &schema{kind: schemaArray, items: &schema{kind: schemaString}, description: parameterDescriptions[includeKey]}
+
+	return s
+}
+
+// parametersSchema function returns the schema of config file parameters, which might be set by profiles as well
+func parametersSchema() *schema {
+	s := schemaOf(reflect.TypeOf(ConfigFile{}))
+	for name, property := range s.properties {
+		property.description = parameterDescriptions[name]
+	}
+
+	return s
+}
+
+// parameterSchema function returns the schema of the config file parameter
+func parameterSchema(name string) *schema {
+	return parametersSchema().properties[name]
+}
+
+func overrideSchema() *schema {
+	return schemaOf(reflect.TypeOf(Override{}))
+}
+
+func baselineSchema() *schema {
+	return schemaOf(reflect.TypeOf(Baseline{}))
+}
+
+func approvalsSchema() *schema {
+	return &schema{kind: schemaObject, properties: map[string]*schema{
+		"approvals": schemaOf(reflect.TypeOf([]Approval{})),
+	}}
+}
+
+// readNode function reads YAML file and checks its content by the schema. All found problems are returned at once
+func readNode(fileName string, s *schema) (*yaml.Node, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+
+	if err := s.check(fileName, &document, ""); err != nil {
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+// check function compares YAML node with the schema. The path is the dotted path of the node used in messages
+func (s *schema) check(fileName string, node *yaml.Node, path string) error {
+	switch node.Kind {
+	case 0: //Empty content
+		return nil
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+
+		return s.check(fileName, node.Content[0], path)
+	case yaml.AliasNode:
+		return s.check(fileName, node.Alias, path)
+	}
+
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null" {
+		return nil
+	}
+
+	problem := func(format string, args ...interface{}) error {
+		return &SchemaError{FileName: fileName, Line: node.Line, Column: node.Column, Message: fmt.Sprintf(format, args...)}
+	}
+
+	var errs []error
+	switch s.kind {
+	case schemaObject, schemaMap:
+		if node.Kind != yaml.MappingNode {
+			return problem("%s must be a mapping", describePath(path))
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			property := s.items
+			if s.kind == schemaObject {
+				property = s.properties[strings.ToLower(key.Value)]
+			}
+
+			if property == nil {
+				message := fmt.Sprintf("unknown key '%s'", key.Value)
+				if len(path) > 0 {
+					message += fmt.Sprintf(" in '%s'", path)
+				}
+				if suggestion := s.suggest(key.Value); len(suggestion) > 0 {
+					message += fmt.Sprintf(", did you mean '%s'?", suggestion)
+				}
+
+				errs = append(errs, &SchemaError{FileName: fileName, Line: key.Line, Column: key.Column, Message: message})
+				continue
+			}
+
+			errs = append(errs, property.check(fileName, value, joinPath(path, key.Value)))
+		}
+	case schemaArray:
+		if node.Kind != yaml.SequenceNode {
+			return problem("%s must be a list", describePath(path))
+		}
+
+		for _, item := range node.Content {
+			errs = append(errs, s.items.check(fileName, item, path))
+		}
+	case schemaBoolean:
+		if node.Kind != yaml.ScalarNode || node.ShortTag() != "!!bool" {
+			return problem("%s must be either true or false", describePath(path))
+		}
+	case schemaInteger:
+		if node.Kind != yaml.ScalarNode || node.ShortTag() != "!!int" {
+			return problem("%s must be an integer", describePath(path))
+		}
+	case schemaDate:
+		if _, err := time.Parse(time.DateOnly, node.Value); node.Kind != yaml.ScalarNode || err != nil {
+			return problem("%s must be a date in YYYY-MM-DD format", describePath(path))
+		}
+	default:
+		if node.Kind != yaml.ScalarNode {
+			return problem("%s must be a string", describePath(path))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// suggest function returns the known key of the object, which is the closest one to the unknown key, if any
+func (s *schema) suggest(key string) string {
+	key = strings.ToLower(key)
+
+	var suggestion string
+	bestDistance := max(2, len(key)/4) + 1
+	for _, name := range slices.Sorted(maps.Keys(s.properties)) {
+		if distance := editDistance(key, name); distance < bestDistance {
+			suggestion, bestDistance = name, distance
+		}
+	}
+
+	return suggestion
+}
+
+// editDistance function returns Levenshtein distance between the strings
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous = current
+	}
+
+	return previous[len(b)]
+}
+
+func joinPath(path string, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func describePath(path string) string {
+	if len(path) == 0 {
+		return "content"
+	}
+
+	return fmt.Sprintf("'%s'", path)
+}
+
+// jsonSchema function returns the schema in form of JSON Schema document
+func (s *schema) jsonSchema() map[string]interface{} {
+	document := make(map[string]interface{})
+	if len(s.description) > 0 {
+		document["description"] = s.description
+	}
+
+	switch s.kind {
+	case schemaObject:
+		properties := make(map[string]interface{})
+		for name, property := range s.properties {
+			properties[name] = property.jsonSchema()
+		}
+
+		document["type"] = "object"
+		document["properties"] = properties
+		document["additionalProperties"] = false
+	case schemaMap:
+		document["type"] = "object"
+		document["additionalProperties"] = s.items.jsonSchema()
+	case schemaArray:
+		document["type"] = "array"
+		document["items"] = s.items.jsonSchema()
+	case schemaDate:
+		document["type"] = "string"
+		document["format"] = "date"
+	default:
+		document["type"] = s.kind
+	}
+
+	return document
+}
+
+// JSONSchema function returns JSON Schema of the config file, e.g. for autocompletion and validation in editors
+func JSONSchema() ([]byte, error) {
+	document := configSchema().jsonSchema()
+	document["$schema"] = "http://json-schema.org/draft-07/schema#"
+	document["title"] = "tf-plan-reporter config file"
+
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(content, '\n'), nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchemaTestSuite struct {
+	suite.Suite
+	tmpDir string
+}
+
+func (ts *SchemaTestSuite) SetupTest() {
+	ts.tmpDir = ts.T().TempDir()
+}
+
+func (ts *SchemaTestSuite) createFile(name string, content string) string {
+	fileName := path.Join(ts.tmpDir, name)
+
+	if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+		ts.T().Fatalf("Could not write file content: %s", fileName) //nolint:typecheck
+	}
+
+	return fileName
+}
+
+func (ts *SchemaTestSuite) TestUnknownKeys() {
+	fileName := ts.createFile("config.yml", `terraform_binary_file: /usr/bin/terraform
+critical_resource: [all]
+ignore_rules:
+  - resource_type: "*"
+    atributes: [tags_all]
+profiles:
+  prod:
+    allowed_removal: [null_resource]
+`)
+
+	_, err := Load(fileName)
+
+	assert.ErrorContains(ts.T(), err, fileName+":2:1: unknown key 'critical_resource', did you mean 'critical_resources'?")                //nolint:typecheck
+	assert.ErrorContains(ts.T(), err, fileName+":5:5: unknown key 'atributes' in 'ignore_rules', did you mean 'attributes'?")              //nolint:typecheck
+	assert.ErrorContains(ts.T(), err, fileName+":8:5: unknown key 'allowed_removal' in 'profiles.prod', did you mean 'allowed_removals'?") //nolint:typecheck
+}
+
+func (ts *SchemaTestSuite) TestWrongTypes() {
+	fileName := ts.createFile("config.yml", `critical_resources: all
+not_use_chdir: sometimes
+outputs:
+  - format: html
+    max_size: big
+`)
+
+	_, err := Load(fileName)
+
+	assert.ErrorContains(ts.T(), err, fileName+":1:21: 'critical_resources' must be a list")          //nolint:typecheck
+	assert.ErrorContains(ts.T(), err, fileName+":2:16: 'not_use_chdir' must be either true or false") //nolint:typecheck
+	assert.ErrorContains(ts.T(), err, fileName+":5:15: 'outputs.max_size' must be an integer")        //nolint:typecheck
+}
+
+func (ts *SchemaTestSuite) TestUnknownKeyWithoutSuggestion() {
+	fileName := ts.createFile("config.yml", "something: else\n")
+
+	_, err := Load(fileName)
+
+	assert.EqualError(ts.T(), err, fileName+":1:1: unknown key 'something'") //nolint:typecheck
+}
+
+func (ts *SchemaTestSuite) TestUnknownKeyOfOverride() {
+	fileName := ts.createFile(OverrideFileName, "critical_resources: [azurerm_key_vault]\nignore_rule: []\n")
+
+	_, err := loadOverride(fileName)
+
+	assert.ErrorContains(ts.T(), err, fileName+":2:1: unknown key 'ignore_rule', did you mean 'ignore_rules'?") //nolint:typecheck
+}
+
+func (ts *SchemaTestSuite) TestLocations() {
+	fileName := ts.createFile("config.yml", `critical_resources: [all]
+allowed_removals: [null_resource]
+profiles:
+  prod:
+    allowed_removals: [time_sleep]
+`)
+	ts.T().Setenv("TFPR_CRITICAL_RESOURCES", "all")
+
+	settings, err := LoadWithOptions(fileName, Options{Profile: "prod"})
+
+	assert.NoError(ts.T(), err)                                                                                    //nolint:typecheck
+	assert.Equal(ts.T(), fileName+":5", settings.Locations["allowed_removals"])                                    //nolint:typecheck
+	assert.Equal(ts.T(), "TFPR_CRITICAL_RESOURCES environment variable", settings.Locations["critical_resources"]) //nolint:typecheck
+}
+
+func (ts *SchemaTestSuite) TestPublishedSchemaIsUpToDate() {
+	published, err := os.ReadFile("../../tf-plan-reporter.schema.json")
+	assert.NoError(ts.T(), err) //nolint:typecheck
+
+	generated, err := JSONSchema()
+	assert.NoError(ts.T(), err) //nolint:typecheck
+
+	var document map[string]interface{}
+	assert.NoError(ts.T(), json.Unmarshal(generated, &document))                                                            //nolint:typecheck
+	assert.Equal(ts.T(), string(generated), string(published), "Regenerate the schema by 'validate-config --print-schema'") //nolint:typecheck
+}
+
+func TestSchema(t *testing.T) {
+	suite.Run(t, new(SchemaTestSuite))
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Starter is the content of the starter config file generated by `init` command from discovered plans
+type Starter struct {
+	TfCmdBinaryFile    string
+	TfPlanFileBasename string
+	SearchFolder       string
+	NotUseTfChDirArg   bool
+	ChDirReason        string   //Why `not_use_chdir` has such value, it's written as comment
+	ResourceTypes      []string //Types of resources found in the plans
+}
+
+var starterConfig = `# tf-plan-reporter config file generated by 'init' command
+# yaml-language-server: $schema=https://raw.githubusercontent.com/arshvin/tf-plan-reporter/main/tf-plan-reporter.schema.json
+terraform_binary_file: {{ .TfCmdBinaryFile | yaml }}
+terraform_plan_file_basename: {{ .TfPlanFileBasename | yaml }}
+terraform_plan_search_folder: {{ .SearchFolder | yaml }}
+
+# {{ .ChDirReason }}
+not_use_chdir: {{ .NotUseTfChDirArg }}
+
+# Removals of all resource types are forbidden, except of the allowed ones
+critical_resources:
+  - all
+{{- with .Critical }}
+
+# Stateful resource types found in the plans, which are kept critical (by the built-in catalog):
+{{- range . }}
+#   - {{ . }}
+{{- end }}
+{{- end }}
+
+# Resource types found in the plans, which are suggested as safe for removal. Review them before use
+allowed_removals:{{ if not .Allowed }} []{{ end }}
+{{- range .Allowed }}
+  - {{ . | yaml }}
+{{- end }}
+`
+
+// Critical function returns the found resource types, which are in the built-in catalog of stateful resource types
+func (s *Starter) Critical() []string {
+	var types []string
+	for _, resourceType := range s.ResourceTypes {
+		if IsStateful(resourceType) {
+			types = append(types, resourceType)
+		}
+	}
+
+	return types
+}
+
+// Allowed function returns the found resource types, which are not in the built-in catalog of stateful resource types
+func (s *Starter) Allowed() []string {
+	var types []string
+	for _, resourceType := range s.ResourceTypes {
+		if !IsStateful(resourceType) {
+			types = append(types, resourceType)
+		}
+	}
+
+	return types
+}
+
+// Render function returns the content of the starter config file
+func (s *Starter) Render() (string, error) {
+	tmpl, err := template.New("starter").Funcs(template.FuncMap{
+		"yaml": func(value string) (string, error) {
+			content, err := yaml.Marshal(value)
+			return strings.TrimSpace(string(content)), err
+		},
+	}).Parse(starterConfig)
+	if err != nil {
+		return "", err
+	}
+
+	var content strings.Builder
+	if err := tmpl.Execute(&content, s); err != nil {
+		return "", err
+	}
+
+	return content.String(), nil
+}
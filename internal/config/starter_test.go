@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStarterConfig(t *testing.T) {
+	starter := &Starter{
+		TfCmdBinaryFile:    "/usr/bin/terraform",
+		TfPlanFileBasename: "plan.bin",
+		SearchFolder:       "live env",
+		ChDirReason:        "Plan files are found in terragrunt cache folders",
+		ResourceTypes:      []string{"azurerm_key_vault", "azurerm_role_assignment", "null_resource"},
+	}
+
+	assert.Equal(t, []string{"azurerm_key_vault"}, starter.Critical())                       //nolint:typecheck
+	assert.Equal(t, []string{"azurerm_role_assignment", "null_resource"}, starter.Allowed()) //nolint:typecheck
+
+	content, err := starter.Render()
+	assert.NoError(t, err)                                 //nolint:typecheck
+	assert.Contains(t, content, "#   - azurerm_key_vault") //nolint:typecheck
+
+	fileName := path.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+		t.Fatalf("Could not write file content: %s", fileName) //nolint:typecheck
+	}
+
+	settings, err := Load(fileName)
+	assert.NoError(t, err)                                                                          //nolint:typecheck
+	assert.Equal(t, "live env", settings.SearchFolder)                                              //nolint:typecheck
+	assert.Equal(t, false, settings.NotUseTfChDirArg)                                               //nolint:typecheck
+	assert.Equal(t, []string{"all"}, settings.CriticalResources)                                    //nolint:typecheck
+	assert.Equal(t, []string{"azurerm_role_assignment", "null_resource"}, settings.AllowedRemovals) //nolint:typecheck
+}
@@ -0,0 +1,65 @@
+package processing
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+)
+
+// approvalMatcher is the approval of the config with its address and module patterns compiled once, when the decision
+// maker is created. An empty pattern is nil and matches anything
+type approvalMatcher struct {
+	approval *config.Approval
+	address  *regexp.Regexp
+	module   *regexp.Regexp
+}
+
+func compileApprovals(approvals []*config.Approval) []*approvalMatcher {
+	var result []*approvalMatcher
+	for _, approval := range approvals {
+		result = append(result, &approvalMatcher{
+			approval: approval,
+			address:  compileGlob(approval.Address),
+			module:   compileGlob(approval.Module),
+		})
+	}
+
+	return result
+}
+
+// ApprovedBy function returns the approval which releases the removal of the resource, or nil if there is none.
+// Only deleted and replaced resources might be approved
+func (dm *DecisionMaker) ApprovedBy(rd *ResourceData) *config.Approval {
+	if !(rd.Actions.Delete() || rd.Actions.Replace()) {
+		return nil
+	}
+
+	for _, matcher := range dm.approvals {
+		if matcher.address != nil && !matcher.address.MatchString(rd.Address) {
+			continue
+		}
+		if matcher.module != nil && !matcher.module.MatchString(rd.Module) {
+			continue
+		}
+
+		return matcher.approval
+	}
+
+	return nil
+}
+
+// compileGlob function returns the regexp of the pattern, where `*` matches any characters (including `/` and `.`)
+// and all other characters are literal, e.g. `module.db.azurerm_key_vault.*` or `prod/*`. It returns nil for empty pattern
+func compileGlob(pattern string) *regexp.Regexp {
+	if len(pattern) == 0 {
+		return nil
+	}
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
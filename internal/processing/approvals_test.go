@@ -0,0 +1,67 @@
+package processing
+
+import (
+	"testing"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+)
+
+type ApprovalsTestSuite struct {
+	suite.Suite
+
+	dm *DecisionMaker
+}
+
+func (ts *ApprovalsTestSuite) SetupTest() {
+	settings := &config.AppConfig{DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{"azurerm_key_vault": true}}}
+	settings.Approvals = []*config.Approval{
+		{Address: "module.kv.azurerm_key_vault.main[0]", Approver: "alice"},
+		{Module: "legacy/*", Approver: "bob"},
+	}
+
+	ts.dm = NewDecisionMaker(settings)
+}
+
+func (ts *ApprovalsTestSuite) TestApprovedByAddress() {
+	approval := ts.dm.ApprovedBy(resource("module.kv.azurerm_key_vault.main[0]", "core", tfJson.ActionDelete))
+
+	assert.NotNil(ts.T(), approval)                                                                                             //nolint:typecheck
+	assert.Equal(ts.T(), "alice", approval.Approver)                                                                            //nolint:typecheck
+	assert.True(ts.T(), evaluated(ts.dm, resource("module.kv.azurerm_key_vault.main[0]", "core", tfJson.ActionDelete)).Allowed) //nolint:typecheck
+	assert.Nil(ts.T(), ts.dm.ApprovedBy(resource("module.kv.azurerm_key_vault.main0", "core", tfJson.ActionDelete)))            //nolint:typecheck
+}
+
+func (ts *ApprovalsTestSuite) TestApprovedByModule() {
+	assert.NotNil(ts.T(), ts.dm.ApprovedBy(resource("azurerm_key_vault.a", "legacy/db", tfJson.ActionDelete))) //nolint:typecheck
+	assert.Nil(ts.T(), ts.dm.ApprovedBy(resource("azurerm_key_vault.a", "core/legacy", tfJson.ActionDelete)))  //nolint:typecheck
+
+	verdict := evaluated(ts.dm, resource("azurerm_key_vault.a", "core", tfJson.ActionDelete))
+	assert.False(ts.T(), verdict.Allowed) //nolint:typecheck
+	assert.Nil(ts.T(), verdict.Approval)  //nolint:typecheck
+
+	verdict = evaluated(ts.dm, resource("azurerm_key_vault.a", "legacy/db", tfJson.ActionDelete))
+	assert.True(ts.T(), verdict.Allowed)                   //nolint:typecheck
+	assert.Equal(ts.T(), "bob", verdict.Approval.Approver) //nolint:typecheck
+}
+
+func (ts *ApprovalsTestSuite) TestOnlyRemovalsApproved() {
+	assert.Nil(ts.T(), ts.dm.ApprovedBy(resource("azurerm_key_vault.a", "legacy/db", tfJson.ActionUpdate))) //nolint:typecheck
+}
+
+// Patterns are compiled once by NewDecisionMaker, empty ones match anything
+func (ts *ApprovalsTestSuite) TestPatternsCompiled() {
+	assert.Len(ts.T(), ts.dm.approvals, 2)                                                                  //nolint:typecheck
+	assert.Equal(ts.T(), `^module\.kv\.azurerm_key_vault\.main\[0\]$`, ts.dm.approvals[0].address.String()) //nolint:typecheck
+	assert.Nil(ts.T(), ts.dm.approvals[0].module)                                                           //nolint:typecheck
+	assert.Nil(ts.T(), ts.dm.approvals[1].address)                                                          //nolint:typecheck
+	assert.Equal(ts.T(), "^legacy/.*$", ts.dm.approvals[1].module.String())                                 //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestApprovals(t *testing.T) {
+	suite.Run(t, new(ApprovalsTestSuite))
+}
@@ -0,0 +1,147 @@
+package processing
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	tfJson "github.com/hashicorp/terraform-json"
+)
+
+const (
+	unknownValue   = "(known after apply)"
+	sensitiveValue = "(sensitive value)"
+)
+
+type AttributeChange struct {
+	Path   string `json:"path"`   //Flattened path of the attribute, like `site_config.ip_restriction[0].name`
+	Before string `json:"before"` //JSON encoded value before the change, empty if the attribute is absent
+	After  string `json:"after"`  //JSON encoded value after the change, empty if the attribute is absent
+}
+
+// attributeChanges function compares `before` and `after` values of the resource change and returns
+// the list of attributes whose values are going to be changed, sorted by attribute path
+func attributeChanges(change *tfJson.Change) []*AttributeChange {
+	before := flattenValue(change.Before)
+	after := flattenValue(change.After)
+
+	beforeSensitive := flattenMarks(change.BeforeSensitive)
+	afterSensitive := flattenMarks(change.AfterSensitive)
+	afterUnknown := flattenMarks(change.AfterUnknown)
+
+	paths := make(map[string]bool)
+	for _, values := range []map[string]string{before, after} {
+		for attrPath := range values {
+			paths[attrPath] = true
+		}
+	}
+	for attrPath := range afterUnknown {
+		paths[attrPath] = true
+	}
+
+	var result []*AttributeChange
+	for attrPath := range paths {
+		beforeValue, beforeFound := before[attrPath]
+		afterValue, afterFound := after[attrPath]
+
+		if isMarked(afterUnknown, attrPath) {
+			afterValue, afterFound = unknownValue, true
+		}
+
+		if beforeFound == afterFound && beforeValue == afterValue {
+			continue
+		}
+
+		if beforeFound && isMarked(beforeSensitive, attrPath) {
+			beforeValue = sensitiveValue
+		}
+		if afterFound && afterValue != unknownValue && isMarked(afterSensitive, attrPath) {
+			afterValue = sensitiveValue
+		}
+
+		result = append(result, &AttributeChange{
+			Path:   attrPath,
+			Before: beforeValue,
+			After:  afterValue,
+		})
+	}
+
+	slices.SortFunc(result, func(a, b *AttributeChange) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return result
+}
+
+// flattenValue function converts the nested object of resource attributes to the flat map,
+// where keys are attribute paths and values are JSON encoded leaf values
+func flattenValue(value interface{}) map[string]string {
+	result := make(map[string]string)
+	walkValue("", value, func(attrPath string, leaf interface{}) {
+		encoded, err := json.Marshal(leaf)
+		if err != nil {
+			encoded = []byte(fmt.Sprint(leaf))
+		}
+		result[attrPath] = string(encoded)
+	})
+
+	return result
+}
+
+// flattenMarks function converts the nested object of booleans (like `after_unknown` or `after_sensitive`)
+// to the set of attribute paths marked by `true` value
+func flattenMarks(value interface{}) map[string]bool {
+	result := make(map[string]bool)
+	walkValue("", value, func(attrPath string, leaf interface{}) {
+		if marked, ok := leaf.(bool); ok && marked {
+			result[attrPath] = true
+		}
+	})
+
+	return result
+}
+
+// isMarked function checks if the attribute path or any of its parents is in the marks set
+func isMarked(marks map[string]bool, attrPath string) bool {
+	if marks[attrPath] {
+		return true
+	}
+
+	for mark := range marks {
+		if mark == "" || strings.HasPrefix(attrPath, mark+".") || strings.HasPrefix(attrPath, mark+"[") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func walkValue(prefix string, value interface{}, visit func(string, interface{})) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		if len(typedValue) == 0 && prefix != "" {
+			visit(prefix, typedValue)
+		}
+		for key, item := range typedValue {
+			if prefix == "" {
+				walkValue(key, item, visit)
+			} else {
+				walkValue(prefix+"."+key, item, visit)
+			}
+		}
+	case []interface{}:
+		if len(typedValue) == 0 && prefix != "" {
+			visit(prefix, typedValue)
+		}
+		for i, item := range typedValue {
+			walkValue(fmt.Sprintf("%s[%d]", prefix, i), item, visit)
+		}
+	case nil:
+		if prefix != "" {
+			visit(prefix, nil)
+		}
+	default:
+		visit(prefix, typedValue)
+	}
+}
@@ -0,0 +1,72 @@
+package processing
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AttributesTestSuite struct {
+	suite.Suite
+}
+
+func (ts *AttributesTestSuite) TestChangedAttributesOnly() {
+	change := &tfJson.Change{
+		Actions: tfJson.Actions{tfJson.ActionUpdate},
+		Before:  map[string]interface{}{"name": "a", "tags": map[string]interface{}{"env": "dev", "team": "x"}},
+		After:   map[string]interface{}{"name": "a", "tags": map[string]interface{}{"env": "prod", "team": "x"}},
+	}
+
+	changes := attributeChanges(change)
+
+	assert.Equal(ts.T(), []*AttributeChange{{Path: "tags.env", Before: `"dev"`, After: `"prod"`}}, changes) //nolint:typecheck
+}
+
+func (ts *AttributesTestSuite) TestUnknownAndSensitiveAttributes() {
+	change := &tfJson.Change{
+		Actions:         tfJson.Actions{tfJson.ActionUpdate},
+		Before:          map[string]interface{}{"id": "1", "secret": "old", "list": []interface{}{"a"}},
+		After:           map[string]interface{}{"secret": "new", "list": []interface{}{"a", "b"}},
+		AfterUnknown:    map[string]interface{}{"id": true},
+		BeforeSensitive: map[string]interface{}{"secret": true},
+		AfterSensitive:  map[string]interface{}{"secret": true},
+	}
+
+	changes := attributeChanges(change)
+
+	assert.Equal(ts.T(), []*AttributeChange{ //nolint:typecheck
+		{Path: "id", Before: `"1"`, After: unknownValue},
+		{Path: "list[1]", Before: "", After: `"b"`},
+		{Path: "secret", Before: sensitiveValue, After: sensitiveValue},
+	}, changes)
+}
+
+func (ts *AttributesTestSuite) TestModuleName() {
+	assert.Equal(ts.T(), "key-vaults", moduleName("/src", "/src/key-vaults/.terragrunt-cache/xx/yy/az-key-vaults/plan.bin")) //nolint:typecheck
+	assert.Equal(ts.T(), path.Join("envs", "prod"), moduleName("/src", "/src/envs/prod/plan.bin"))                           //nolint:typecheck
+	assert.Equal(ts.T(), ".", moduleName("/src", "/src/plan.bin"))                                                           //nolint:typecheck
+}
+
+func (ts *AttributesTestSuite) TestReadPlanJson() {
+	fileName := path.Join(ts.T().TempDir(), "show.json")
+	_ = os.WriteFile(fileName, []byte(`{"format_version": "1.2", "resource_changes": [
+		{"address": "null_resource.a", "type": "null_resource", "name": "a", "change": {"actions": ["delete"]}}
+	]}`), 0644)
+
+	plan, err := ReadPlanJson(fileName)
+	assert.Nil(ts.T(), err)                                                  //nolint:typecheck
+	assert.Equal(ts.T(), "null_resource.a", plan.ResourceChanges[0].Address) //nolint:typecheck
+
+	_ = os.WriteFile(fileName, []byte("{}"), 0644)
+	_, err = ReadPlanJson(fileName)
+	assert.NotNil(ts.T(), err) //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestAttributes(t *testing.T) {
+	suite.Run(t, new(AttributesTestSuite))
+}
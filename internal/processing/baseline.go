@@ -0,0 +1,91 @@
+package processing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+)
+
+// AcceptedBy function returns the baseline entry which accepts the planned change of the resource, or nil if the change
+// is not accepted. If the entries of the resource have attribute paths, the update is accepted only if all changed
+// attributes are covered by them. Expired entries don't accept anything
+func (dm *DecisionMaker) AcceptedBy(rd *ResourceData) *config.AcceptedChange {
+	if dm.config == nil {
+		return nil
+	}
+
+	var covering *config.AcceptedChange
+	covered := make(map[string]bool)
+
+	for _, accepted := range dm.config.Accepted {
+		if accepted.Address != rd.Address || accepted.Action != rd.ActionLabel() {
+			continue
+		}
+		if len(accepted.Module) > 0 && accepted.Module != rd.Module {
+			continue
+		}
+		if isExpired(accepted, time.Now()) {
+			continue
+		}
+
+		if len(accepted.Attribute) == 0 {
+			return accepted
+		}
+
+		for _, change := range rd.Changes {
+			if isAttributeCovered(accepted.Attribute, change.Path) {
+				covered[change.Path] = true
+				covering = accepted
+			}
+		}
+	}
+
+	if covering == nil || len(covered) < len(rd.Changes) {
+		return nil
+	}
+
+	return covering
+}
+
+// ExpiredAcceptances function returns the descriptions of expired baseline entries
+func (dm *DecisionMaker) ExpiredAcceptances() []string {
+	var result []string
+
+	if dm.config == nil {
+		return result
+	}
+
+	for _, accepted := range dm.config.Accepted {
+		if isExpired(accepted, time.Now()) {
+			result = append(result, fmt.Sprintf("%s %s of %s expired on %s",
+				accepted.Action, describeAcceptance(accepted), accepted.Address, accepted.Expires.Format(time.DateOnly)))
+		}
+	}
+
+	return result
+}
+
+func describeAcceptance(accepted *config.AcceptedChange) string {
+	if len(accepted.Attribute) > 0 {
+		return fmt.Sprintf("acceptance of attribute %s", accepted.Attribute)
+	}
+
+	return "acceptance"
+}
+
+// isExpired function checks if the entry is expired. The entry is valid till the end of its expiry date
+func isExpired(accepted *config.AcceptedChange, now time.Time) bool {
+	return !accepted.Expires.IsZero() && now.After(accepted.Expires.AddDate(0, 0, 1))
+}
+
+// isAttributeCovered function checks if the attribute path is the accepted one or nested into it,
+// e.g. `tags` covers `tags.env` and `rule[0]` covers `rule[0].name`
+func isAttributeCovered(acceptedPath string, attrPath string) bool {
+	if attrPath == acceptedPath {
+		return true
+	}
+
+	return strings.HasPrefix(attrPath, acceptedPath+".") || strings.HasPrefix(attrPath, acceptedPath+"[")
+}
@@ -0,0 +1,91 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+)
+
+type BaselineTestSuite struct {
+	suite.Suite
+
+	dm *DecisionMaker
+}
+
+func (ts *BaselineTestSuite) SetupTest() {
+	settings := &config.AppConfig{DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{"azurerm_key_vault": true}}}
+	settings.Accepted = []*config.AcceptedChange{
+		{Address: "azurerm_key_vault.legacy", Action: "delete", Justification: "Migrated"},
+		{Address: "azurerm_key_vault.other", Module: "core", Action: "delete"},
+		{Address: "azurerm_key_vault.expired", Action: "delete", Expires: time.Now().AddDate(0, 0, -2)},
+		{Address: "azurerm_key_vault.today", Action: "delete", Expires: time.Now().Truncate(24 * time.Hour)},
+		{Address: "azurerm_storage_account.logs", Action: "update", Attribute: "network_rules"},
+	}
+
+	ts.dm = NewDecisionMaker(settings)
+}
+
+func resource(address string, module string, action tfJson.Action, paths ...string) *ResourceData {
+	rd := &ResourceData{Address: address, Module: module, Type: "azurerm_key_vault", Actions: tfJson.Actions{action}}
+	for _, attrPath := range paths {
+		rd.Changes = append(rd.Changes, &AttributeChange{Path: attrPath})
+	}
+
+	return rd
+}
+
+// evaluated function returns the verdict stored on the resource by the evaluation of the plans with it only
+func evaluated(dm *DecisionMaker, rd *ResourceData) *Verdict {
+	dm.Evaluate(&ConsolidatedJson{Deleted: []*ResourceData{rd}})
+
+	return rd.Verdict
+}
+
+func (ts *BaselineTestSuite) TestRemovalAccepted() {
+	accepted := ts.dm.AcceptedBy(resource("azurerm_key_vault.legacy", "any", tfJson.ActionDelete))
+
+	assert.NotNil(ts.T(), accepted)                          //nolint:typecheck
+	assert.Equal(ts.T(), "Migrated", accepted.Justification) //nolint:typecheck
+
+	verdict := evaluated(ts.dm, resource("azurerm_key_vault.legacy", "any", tfJson.ActionDelete))
+	assert.True(ts.T(), verdict.Allowed)                             //nolint:typecheck
+	assert.Equal(ts.T(), "Migrated", verdict.Accepted.Justification) //nolint:typecheck
+
+	verdict = evaluated(ts.dm, resource("azurerm_key_vault.main", "any", tfJson.ActionDelete))
+	assert.False(ts.T(), verdict.Allowed) //nolint:typecheck
+	assert.Nil(ts.T(), verdict.Accepted)  //nolint:typecheck
+
+	assert.Nil(ts.T(), ts.dm.AcceptedBy(resource("azurerm_key_vault.legacy", "any", tfJson.ActionUpdate))) //nolint:typecheck
+}
+
+func (ts *BaselineTestSuite) TestModuleMatched() {
+	assert.NotNil(ts.T(), ts.dm.AcceptedBy(resource("azurerm_key_vault.other", "core", tfJson.ActionDelete))) //nolint:typecheck
+	assert.Nil(ts.T(), ts.dm.AcceptedBy(resource("azurerm_key_vault.other", "extra", tfJson.ActionDelete)))   //nolint:typecheck
+}
+
+func (ts *BaselineTestSuite) TestExpiry() {
+	assert.Nil(ts.T(), ts.dm.AcceptedBy(resource("azurerm_key_vault.expired", "core", tfJson.ActionDelete)))  //nolint:typecheck
+	assert.NotNil(ts.T(), ts.dm.AcceptedBy(resource("azurerm_key_vault.today", "core", tfJson.ActionDelete))) //nolint:typecheck
+
+	expired := ts.dm.ExpiredAcceptances()
+	assert.Equal(ts.T(), 1, len(expired))                                                 //nolint:typecheck
+	assert.Contains(ts.T(), expired[0], "delete acceptance of azurerm_key_vault.expired") //nolint:typecheck
+}
+
+func (ts *BaselineTestSuite) TestAttributesCovered() {
+	address := "azurerm_storage_account.logs"
+
+	assert.NotNil(ts.T(), ts.dm.AcceptedBy(resource(address, "core", tfJson.ActionUpdate, "network_rules[0].ip_rules"))) //nolint:typecheck
+	assert.Nil(ts.T(), ts.dm.AcceptedBy(resource(address, "core", tfJson.ActionUpdate, "network_rules_extra")))          //nolint:typecheck
+	assert.Nil(ts.T(), ts.dm.AcceptedBy(resource(address, "core", tfJson.ActionUpdate, "network_rules", "tags.env")))    //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestBaseline(t *testing.T) {
+	suite.Run(t, new(BaselineTestSuite))
+}
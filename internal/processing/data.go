@@ -10,9 +10,15 @@ import (
 )
 
 type ResourceData struct {
-	Type  string
-	Name  string
-	Index string
+	Address string
+	Module  string //Name of terragrunt/terraform module (folder) whose TF plan file contains the resource
+	Type    string
+	Name    string
+	Index   string
+	Actions tfJson.Actions
+	Changes []*AttributeChange
+	Noise   bool     //The update changes ignored attributes only, so the resource is reported as unchanged one
+	Verdict *Verdict //Decision of the gate over the planned change, nil until the plans are evaluated
 }
 
 type ConsolidatedJson struct {
@@ -20,15 +26,54 @@ type ConsolidatedJson struct {
 	Updated   []*ResourceData
 	Deleted   []*ResourceData
 	Unchanged []*ResourceData
+	Read      []*ResourceData
+	Modules   []string //All modules whose TF plan files have been parsed, including ones without any changes
+
+	ExpiredAcceptances []string //Descriptions of expired baseline entries, filled by the evaluation
+}
+
+// ActionLabel function returns the short human readable name of planned action of the resource
+func (rd *ResourceData) ActionLabel() string {
+	switch {
+	case rd.Actions.Replace():
+		return "replace"
+	case rd.Actions.Create():
+		return "create"
+	case rd.Actions.Delete():
+		return "delete"
+	case rd.Actions.Update():
+		return "update"
+	case rd.Actions.Read():
+		return "read"
+	default:
+		return "no-op"
+	}
+}
+
+// SuppressedItems function returns amount of updates reported as unchanged, since only ignored attributes are changed
+func (cj *ConsolidatedJson) SuppressedItems() int {
+	count := 0
+	for _, item := range cj.Unchanged {
+		if item.Noise {
+			count++
+		}
+	}
+
+	return count
 }
 
 // totalItems function returns amount of all items in the consolidatedJson struct
 func (cj *ConsolidatedJson) TotalItems() int {
-	return len(cj.Created) + len(cj.Updated) + len(cj.Deleted) + len(cj.Unchanged)
+	return len(cj.Created) + len(cj.Updated) + len(cj.Deleted) + len(cj.Unchanged) + len(cj.Read)
 }
 
-// parse function parses input data as parameter and puts it to consolidatedJson struct
-func (cj *ConsolidatedJson) Parse(entity *tfJson.Plan) {
+// parse function parses input data as parameter and puts it to consolidatedJson struct. The decision maker detects
+// updates of ignored attributes only, it might be nil if there are no ignore rules
+func (cj *ConsolidatedJson) Parse(module string, entity *tfJson.Plan, dm *DecisionMaker) {
+	if !slices.Contains(cj.Modules, module) {
+		cj.Modules = append(cj.Modules, module)
+	}
+
 	for _, resource := range entity.ResourceChanges {
 
 		var resourceIndex string
@@ -44,15 +89,23 @@ func (cj *ConsolidatedJson) Parse(entity *tfJson.Plan) {
 		}
 
 		resourceItem := &ResourceData{
-			Type:  resource.Type,
-			Name:  resource.Name,
-			Index: resourceIndex,
+			Address: resource.Address,
+			Module:  module,
+			Type:    resource.Type,
+			Name:    resource.Name,
+			Index:   resourceIndex,
+			Actions: resource.Change.Actions,
+		}
+
+		if !resource.Change.Actions.NoOp() && !resource.Change.Actions.Read() {
+			resourceItem.Changes = attributeChanges(resource.Change)
 		}
 
 		tableRecordContext := log.WithFields(log.Fields{
 			"resource_type":  resourceItem.Type,
 			"resource_name":  resourceItem.Name,
 			"resource_index": resourceItem.Index,
+			"module":         resourceItem.Module,
 		})
 		tableRecordContext.Debug("Created new resource item of report table")
 
@@ -67,13 +120,24 @@ func (cj *ConsolidatedJson) Parse(entity *tfJson.Plan) {
 		}
 
 		if slices.Contains(resource.Change.Actions, tfJson.ActionUpdate) {
-			cj.Updated = append(cj.Updated, resourceItem)
-			tableRecordContext.Debug("The item has been put to 'Updated' list")
+			if dm.IsNoise(resourceItem) {
+				resourceItem.Noise = true
+				cj.Unchanged = append(cj.Unchanged, resourceItem)
+				tableRecordContext.Debug("The item has been put to 'Unchanged' list, since only ignored attributes are updated")
+			} else {
+				cj.Updated = append(cj.Updated, resourceItem)
+				tableRecordContext.Debug("The item has been put to 'Updated' list")
+			}
 		}
 
 		if slices.Contains(resource.Change.Actions, tfJson.ActionNoop) {
 			cj.Unchanged = append(cj.Unchanged, resourceItem)
 			tableRecordContext.Debug("The item has been put to 'Unchanged' list")
 		}
+
+		if slices.Contains(resource.Change.Actions, tfJson.ActionRead) {
+			cj.Read = append(cj.Read, resourceItem)
+			tableRecordContext.Debug("The item has been put to 'Read' list")
+		}
 	}
 }
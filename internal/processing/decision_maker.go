@@ -8,30 +8,22 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-var (
-	instance *DecisionMaker
-)
-
 const (
 	tfProvidersFolderName = ".terraform/providers"
 )
 
+// DecisionMaker evaluates the planned changes by the policy of the config, the baseline and approvals. Every caller
+// creates its own instance for the config by NewDecisionMaker and passes it to the functions needing it. It's never
+// changed after the creation, so it's safe for concurrent use
 type DecisionMaker struct {
-	config                *config.AppConfig
-	criticalRemovalsFound bool
-	IsAllowedForRemoval   func(string) bool
-}
-
-func GetDecisionMaker() *DecisionMaker {
-	if instance == nil {
-		instance = new(DecisionMaker)
-	}
-
-	return instance
+	config              *config.AppConfig
+	approvals           []*approvalMatcher //Approvals of the config with compiled patterns, in the same order
+	IsAllowedForRemoval func(string) bool
 }
 
-func (dm *DecisionMaker) SetConfig(config *config.AppConfig) {
-	dm.config = config
+// NewDecisionMaker function returns the decision maker evaluating the planned changes by the config
+func NewDecisionMaker(config *config.AppConfig) *DecisionMaker {
+	dm := &DecisionMaker{config: config, approvals: compileApprovals(config.Approvals)}
 
 	if dm.config.IsAllCriticalSpecified {
 		dm.IsAllowedForRemoval = dm.isInAllowedList
@@ -39,10 +31,26 @@ func (dm *DecisionMaker) SetConfig(config *config.AppConfig) {
 		dm.IsAllowedForRemoval = dm.isInRescueList
 	}
 
+	return dm
 }
 
-func (dm *DecisionMaker) CriticalRemovalsFound() bool {
-	return dm.criticalRemovalsFound
+// IsAllowedByPolicy function checks if the removal of the resource is allowed by the policy of its module, which is
+// the policy of the config file with per-directory overrides applied
+func (dm *DecisionMaker) IsAllowedByPolicy(rd *ResourceData) bool {
+	if len(dm.config.Overrides) == 0 {
+		return dm.IsAllowedForRemoval(rd.Type)
+	}
+
+	policy := dm.config.PolicyFor(rd.Module)
+	allowed := policy.IsAllowed(rd.Type)
+
+	log.WithFields(log.Fields{
+		"resource_type": rd.Type,
+		"module":        rd.Module,
+		"overrides":     policy.Overrides,
+	}).Debugf("Is it allowed to delete by policy of the module: %v", allowed)
+
+	return allowed
 }
 
 func (dm *DecisionMaker) isInAllowedList(resourceType string) bool {
@@ -53,7 +61,6 @@ func (dm *DecisionMaker) isInAllowedList(resourceType string) bool {
 	}
 
 	log.Debugf("Resource type %s WAS NOT found in IgnoreList -> Forbidden to delete", resourceType)
-	dm.criticalRemovalsFound = true
 
 	return false
 
@@ -62,7 +69,6 @@ func (dm *DecisionMaker) isInAllowedList(resourceType string) bool {
 func (dm *DecisionMaker) isInRescueList(resourceType string) bool {
 	if _, ok := dm.config.ExceptionalResources[resourceType]; ok {
 		log.Debugf("Resource type %s found in RescueList -> Forbidden to delete", resourceType)
-		dm.criticalRemovalsFound = true
 
 		return false
 	}
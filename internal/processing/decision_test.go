@@ -26,8 +26,7 @@ func (ts *DecisionTestSuite) SetupSuite() {
 
 func (ts *DecisionTestSuite) TestIfDeletingIsAllowed() {
 	ts.settings.IsAllCriticalSpecified = true
-	dm := GetDecisionMaker()
-	dm.SetConfig(ts.settings)
+	dm := NewDecisionMaker(ts.settings)
 
 	assert.True(ts.T(), dm.IsAllowedForRemoval("resource1"))  //nolint:typecheck
 	assert.True(ts.T(), dm.IsAllowedForRemoval("resource2"))  //nolint:typecheck
@@ -37,8 +36,7 @@ func (ts *DecisionTestSuite) TestIfDeletingIsAllowed() {
 
 func (ts *DecisionTestSuite) TestIfDeletingIsForbidden() {
 	ts.settings.IsAllCriticalSpecified = false
-	dm := GetDecisionMaker()
-	dm.SetConfig(ts.settings)
+	dm := NewDecisionMaker(ts.settings)
 
 	assert.False(ts.T(), dm.IsAllowedForRemoval("resource1")) //nolint:typecheck
 	assert.False(ts.T(), dm.IsAllowedForRemoval("resource2")) //nolint:typecheck
@@ -46,6 +44,20 @@ func (ts *DecisionTestSuite) TestIfDeletingIsForbidden() {
 	assert.True(ts.T(), dm.IsAllowedForRemoval("resource4"))  //nolint:typecheck
 }
 
+func (ts *DecisionTestSuite) TestIfDeletingIsForbiddenByOverride() {
+	ts.settings.IsAllCriticalSpecified = true
+	ts.settings.Overrides = map[string]*config.Override{
+		"key-vaults": {CriticalResources: []string{"resource1"}},
+	}
+	defer func() { ts.settings.Overrides = nil }()
+
+	dm := NewDecisionMaker(ts.settings)
+
+	assert.True(ts.T(), dm.IsAllowedByPolicy(&ResourceData{Type: "resource1", Module: "rgs"}))              //nolint:typecheck
+	assert.False(ts.T(), dm.IsAllowedByPolicy(&ResourceData{Type: "resource1", Module: "key-vaults/main"})) //nolint:typecheck
+	assert.True(ts.T(), dm.IsAllowedByPolicy(&ResourceData{Type: "resource2", Module: "key-vaults/main"}))  //nolint:typecheck
+}
+
 // Entry point for the test suite
 func TestSettingsValidator(t *testing.T) {
 	suite.Run(t, new(DecisionTestSuite))
@@ -0,0 +1,121 @@
+package processing
+
+import (
+	"archive/zip"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	tfPlanArchiveEntry = "tfplan" //Entry of zip archive, which terraform generated binary plan file actually is
+)
+
+// PlanDiscovery is the layout of TF plan files found in the search folder, e.g. for generating of starter config
+type PlanDiscovery struct {
+	PlanFiles          []string
+	TerragruntCache    bool //Some plan files are inside of terragrunt cache folders
+	ProvidersNearPlans bool //Every plan file has terraform providers folder next to it
+	ProvidersInCwd     bool //Terraform providers folder is in current working directory
+}
+
+// DiscoverPlans function finds TF plan files with the base name in the search folder and detects where terraform
+// providers folders are located
+func DiscoverPlans(searchFolder string, planBaseFileName string, cwd string) (*PlanDiscovery, error) {
+	planFiles, err := findAllTFPlanFiles(searchFolder, planBaseFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	discovery := &PlanDiscovery{
+		PlanFiles:      planFiles,
+		ProvidersInCwd: TfProviderFolderExist(cwd),
+	}
+
+	discovery.ProvidersNearPlans = len(discovery.PlanFiles) > 0
+	for _, planPath := range discovery.PlanFiles {
+		if slices.Contains(strings.Split(planPath, string(filepath.Separator)), tgCacheFolderName) {
+			discovery.TerragruntCache = true
+		}
+
+		if !TfProviderFolderExist(filepath.Dir(planPath)) {
+			discovery.ProvidersNearPlans = false
+		}
+	}
+
+	return discovery, nil
+}
+
+// NotUseChDir function returns the suggested value of `not_use_chdir` config file parameter: terraform is run in
+// current working directory only if the plan files are neither in terragrunt cache nor next to providers folders
+func (d *PlanDiscovery) NotUseChDir() bool {
+	return !d.TerragruntCache && !d.ProvidersNearPlans && d.ProvidersInCwd
+}
+
+// DetectPlanBasename function returns the most common base name of terraform generated binary plan files found in the
+// search folder, or empty string if there are no such files. Terraform folders are skipped
+func DetectPlanBasename(searchFolder string) string {
+	counts := make(map[string]int)
+
+	_ = filepath.WalkDir(searchFolder, func(currentPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //Unreadable folders are just skipped
+		}
+
+		if d.IsDir() {
+			switch d.Name() {
+			case ".terraform", ".git":
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.Type().IsRegular() && isTFPlanArchive(currentPath) {
+			log.Debugf("Found terraform generated binary plan file: %s", currentPath)
+			counts[d.Name()]++
+		}
+
+		return nil
+	})
+
+	var basename string
+	for _, name := range slices.Sorted(maps.Keys(counts)) {
+		if counts[name] > counts[basename] {
+			basename = name
+		}
+	}
+
+	return basename
+}
+
+// isTFPlanArchive function checks if the file is terraform generated binary plan file, which is zip archive with
+// `tfplan` entry
+func isTFPlanArchive(fileName string) bool {
+	archive, err := zip.OpenReader(fileName)
+	if err != nil {
+		return false
+	}
+	defer archive.Close()
+
+	return slices.ContainsFunc(archive.File, func(file *zip.File) bool { return file.Name == tfPlanArchiveEntry })
+}
+
+// ResourceTypes function returns sorted types of all resources of the plans, including unchanged ones
+func (cj *ConsolidatedJson) ResourceTypes() []string {
+	var types []string
+	for _, items := range [][]*ResourceData{cj.Created, cj.Updated, cj.Deleted, cj.Unchanged, cj.Read} {
+		for _, item := range items {
+			if !slices.Contains(types, item.Type) {
+				types = append(types, item.Type)
+			}
+		}
+	}
+	slices.Sort(types)
+
+	return types
+}
@@ -0,0 +1,99 @@
+package processing
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DiscoveryTestSuite struct {
+	suite.Suite
+	tmpDir string
+}
+
+func (ts *DiscoveryTestSuite) SetupTest() {
+	ts.tmpDir = ts.T().TempDir()
+}
+
+func (ts *DiscoveryTestSuite) mkdir(name string) string {
+	folder := filepath.Join(ts.tmpDir, name)
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		ts.T().Fatalf("Could not create folder: %s", folder) //nolint:typecheck
+	}
+
+	return folder
+}
+
+func (ts *DiscoveryTestSuite) createPlan(name string) {
+	file, err := os.Create(filepath.Join(ts.mkdir(filepath.Dir(name)), filepath.Base(name)))
+	if err != nil {
+		ts.T().Fatalf("Could not create plan file: %s", name) //nolint:typecheck
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	if _, err := archive.Create(tfPlanArchiveEntry); err != nil {
+		ts.T().Fatalf("Could not write plan file: %s", name) //nolint:typecheck
+	}
+	archive.Close()
+}
+
+func (ts *DiscoveryTestSuite) TestTerragruntLayout() {
+	ts.createPlan("rgs/.terragrunt-cache/xx/mod/plan.bin")
+	ts.mkdir("rgs/.terragrunt-cache/xx/mod/" + tfProvidersFolderName)
+	ts.createPlan("kv/.terragrunt-cache/xx/mod/plan.bin")
+	ts.mkdir("kv/.terragrunt-cache/xx/mod/" + tfProvidersFolderName)
+
+	discovery, err := DiscoverPlans(ts.tmpDir, "plan.bin", ts.tmpDir)
+	assert.NoError(ts.T(), err) //nolint:typecheck
+
+	assert.Equal(ts.T(), 2, len(discovery.PlanFiles)) //nolint:typecheck
+	assert.True(ts.T(), discovery.TerragruntCache)    //nolint:typecheck
+	assert.True(ts.T(), discovery.ProvidersNearPlans) //nolint:typecheck
+	assert.False(ts.T(), discovery.NotUseChDir())     //nolint:typecheck
+}
+
+func (ts *DiscoveryTestSuite) TestTerraformLayout() {
+	ts.createPlan("plan.bin")
+	ts.mkdir("work/" + tfProvidersFolderName)
+
+	discovery, err := DiscoverPlans(ts.tmpDir, "plan.bin", filepath.Join(ts.tmpDir, "work"))
+	assert.NoError(ts.T(), err) //nolint:typecheck
+
+	assert.False(ts.T(), discovery.TerragruntCache)    //nolint:typecheck
+	assert.False(ts.T(), discovery.ProvidersNearPlans) //nolint:typecheck
+	assert.True(ts.T(), discovery.NotUseChDir())       //nolint:typecheck
+}
+
+func (ts *DiscoveryTestSuite) TestDetectPlanBasename() {
+	ts.createPlan("a/tfplan.bin")
+	ts.createPlan("b/tfplan.bin")
+	ts.createPlan("c/plan.out")
+	ts.createPlan(".terraform/cached.bin")
+	if err := os.WriteFile(filepath.Join(ts.tmpDir, "a", "plan.json"), []byte("{}"), 0644); err != nil {
+		ts.T().Fatal(err) //nolint:typecheck
+	}
+
+	assert.Equal(ts.T(), "tfplan.bin", DetectPlanBasename(ts.tmpDir)) //nolint:typecheck
+	assert.Equal(ts.T(), "", DetectPlanBasename(ts.mkdir("nothing"))) //nolint:typecheck
+}
+
+func (ts *DiscoveryTestSuite) TestResourceTypes() {
+	data := new(ConsolidatedJson)
+	data.Parse("mod", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		{Address: "b.x", Type: "b", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionNoop}}},
+		{Address: "a.x", Type: "a", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionDelete}}},
+		{Address: "b.y", Type: "b", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionCreate}}},
+	}}, nil)
+
+	assert.Equal(ts.T(), []string{"a", "b"}, data.ResourceTypes()) //nolint:typecheck
+}
+
+func TestDiscovery(t *testing.T) {
+	suite.Run(t, new(DiscoveryTestSuite))
+}
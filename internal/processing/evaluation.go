@@ -0,0 +1,90 @@
+package processing
+
+import (
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// Verdict is the decision of the gate over the planned change of the resource, it's set by Evaluate
+type Verdict struct {
+	Accepted *config.AcceptedChange //Baseline entry accepting the planned change, if any
+	Approval *config.Approval       //Approval releasing the removal, if any
+	Allowed  bool                   //Whether the change is allowed: accepted, approved or allowed by the policy. Changes other than removals are always allowed
+}
+
+// Evaluate function decides over every planned change of the plans by the baseline, approvals and the policy of its
+// module, and stores the verdicts on the resources. It doesn't change the decision maker, so it might be shared by
+// several evaluations. Renderers and the gate only read the verdicts afterwards
+func (dm *DecisionMaker) Evaluate(cj *ConsolidatedJson) {
+	for _, items := range [][]*ResourceData{cj.Created, cj.Updated, cj.Deleted} {
+		for _, item := range items {
+			if item.Verdict = dm.verdict(item); !item.Verdict.Allowed {
+				log.WithFields(log.Fields{
+					"address": item.Address,
+					"module":  item.Module,
+				}).Debug("Removal of critical resource is found")
+			}
+		}
+	}
+
+	cj.ExpiredAcceptances = dm.ExpiredAcceptances()
+}
+
+func (dm *DecisionMaker) verdict(rd *ResourceData) *Verdict {
+	verdict := &Verdict{Accepted: dm.AcceptedBy(rd), Allowed: true}
+
+	if verdict.Accepted != nil || !(rd.Actions.Delete() || rd.Actions.Replace()) {
+		return verdict
+	}
+
+	if verdict.Approval = dm.ApprovedBy(rd); verdict.Approval == nil {
+		verdict.Allowed = dm.IsAllowedByPolicy(rd)
+	}
+
+	return verdict
+}
+
+// IsAllowed function returns whether the planned change of the resource is allowed by the gate. Removals of not
+// evaluated resources are never allowed
+func (rd *ResourceData) IsAllowed() bool {
+	if rd.Verdict == nil {
+		return !(rd.Actions.Delete() || rd.Actions.Replace())
+	}
+
+	return rd.Verdict.Allowed
+}
+
+// AcceptedBy function returns the baseline entry which has accepted the planned change of the evaluated resource, or nil
+func (rd *ResourceData) AcceptedBy() *config.AcceptedChange {
+	if rd.Verdict == nil {
+		return nil
+	}
+
+	return rd.Verdict.Accepted
+}
+
+// ApprovedBy function returns the approval which has released the removal of the evaluated resource, or nil
+func (rd *ResourceData) ApprovedBy() *config.Approval {
+	if rd.Verdict == nil {
+		return nil
+	}
+
+	return rd.Verdict.Approval
+}
+
+// CriticalRemovals function returns the deleted and replaced resources, whose removals are not allowed by the gate
+func (cj *ConsolidatedJson) CriticalRemovals() []*ResourceData {
+	var result []*ResourceData
+	for _, item := range cj.Deleted {
+		if !item.IsAllowed() {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// CriticalRemovalsFound function checks if there are removals not allowed by the gate
+func (cj *ConsolidatedJson) CriticalRemovalsFound() bool {
+	return len(cj.CriticalRemovals()) > 0
+}
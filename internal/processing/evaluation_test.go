@@ -0,0 +1,79 @@
+package processing
+
+import (
+	"testing"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+)
+
+type EvaluationTestSuite struct {
+	suite.Suite
+
+	dm *DecisionMaker
+}
+
+func (ts *EvaluationTestSuite) SetupTest() {
+	settings := &config.AppConfig{DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{"azurerm_key_vault": true}}}
+	settings.Accepted = []*config.AcceptedChange{{Address: "azurerm_key_vault.legacy", Action: "delete", Justification: "Migrated"}}
+	settings.Approvals = []*config.Approval{{Module: "legacy", Approver: "alice"}}
+
+	ts.dm = NewDecisionMaker(settings)
+}
+
+func plans(module string, changes ...*ResourceData) *ConsolidatedJson {
+	data := new(ConsolidatedJson)
+
+	var resourceChanges []*tfJson.ResourceChange
+	for _, change := range changes {
+		resourceChanges = append(resourceChanges, &tfJson.ResourceChange{Address: change.Address, Type: change.Type, Change: &tfJson.Change{Actions: change.Actions}})
+	}
+	data.Parse(module, &tfJson.Plan{ResourceChanges: resourceChanges}, nil)
+
+	return data
+}
+
+func (ts *EvaluationTestSuite) TestVerdictsStored() {
+	data := plans("core",
+		resource("azurerm_key_vault.main", "", tfJson.ActionDelete),
+		resource("azurerm_key_vault.legacy", "", tfJson.ActionDelete),
+		resource("azurerm_key_vault.new", "", tfJson.ActionCreate),
+	)
+	ts.dm.Evaluate(data)
+
+	assert.False(ts.T(), data.Deleted[0].Verdict.Allowed)                           //nolint:typecheck
+	assert.Equal(ts.T(), "Migrated", data.Deleted[1].AcceptedBy().Justification)    //nolint:typecheck
+	assert.True(ts.T(), data.Deleted[1].IsAllowed())                                //nolint:typecheck
+	assert.True(ts.T(), data.Created[0].IsAllowed())                                //nolint:typecheck
+	assert.Equal(ts.T(), []*ResourceData{data.Deleted[0]}, data.CriticalRemovals()) //nolint:typecheck
+}
+
+func (ts *EvaluationTestSuite) TestDecisionMakerShared() {
+	core := plans("core", &ResourceData{Address: "azurerm_key_vault.main", Type: "azurerm_key_vault", Actions: tfJson.Actions{tfJson.ActionDelete, tfJson.ActionCreate}})
+	legacy := plans("legacy", resource("azurerm_key_vault.main", "", tfJson.ActionDelete))
+	ts.dm.Evaluate(core)
+	ts.dm.Evaluate(legacy)
+
+	assert.True(ts.T(), core.CriticalRemovalsFound())                      //nolint:typecheck
+	assert.False(ts.T(), legacy.CriticalRemovalsFound())                   //nolint:typecheck
+	assert.Equal(ts.T(), "alice", legacy.Deleted[0].ApprovedBy().Approver) //nolint:typecheck
+}
+
+func (ts *EvaluationTestSuite) TestRemovalNotEvaluated() {
+	data := plans("core",
+		resource("null_resource.a", "", tfJson.ActionDelete),
+		resource("null_resource.b", "", tfJson.ActionUpdate),
+	)
+
+	assert.True(ts.T(), data.CriticalRemovalsFound()) //nolint:typecheck
+	assert.True(ts.T(), data.Updated[0].IsAllowed())  //nolint:typecheck
+	assert.Nil(ts.T(), data.Deleted[0].AcceptedBy())  //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestEvaluation(t *testing.T) {
+	suite.Run(t, new(EvaluationTestSuite))
+}
@@ -0,0 +1,107 @@
+package processing
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Explanation is the trail of decisions taken over the planned change of the resource, e.g. for `explain` command
+type Explanation struct {
+	Resource *ResourceData
+	Removal  bool     //Whether the gate applies to the change at all, i.e. the resource is deleted or replaced
+	Allowed  bool     //Whether the change is allowed by the gate
+	Reasons  []string //Decisions in order of their evaluation
+}
+
+// Find function returns the resources of the plans with the address, one per module. Replaced resources are returned once
+func (cj *ConsolidatedJson) Find(address string) []*ResourceData {
+	var found []*ResourceData
+	for _, items := range [][]*ResourceData{cj.Created, cj.Updated, cj.Deleted, cj.Unchanged, cj.Read} {
+		for _, item := range items {
+			if item.Address == address && !slices.Contains(found, item) {
+				found = append(found, item)
+			}
+		}
+	}
+
+	return found
+}
+
+// Explain function describes the verdicts stored by Evaluate for the resources with the address, one per module. The
+// plans are not evaluated again, so the explanation always matches the verdict of the gate. The removal without stored
+// verdict is an error, since it means the plans haven't been evaluated
+func (dm *DecisionMaker) Explain(cj *ConsolidatedJson, address string) ([]*Explanation, error) {
+	var result []*Explanation
+	for _, rd := range cj.Find(address) {
+		if rd.Verdict == nil && (rd.Actions.Delete() || rd.Actions.Replace()) {
+			return nil, fmt.Errorf("removal of '%s' in module '%s' is not evaluated", rd.Address, rd.Module)
+		}
+
+		result = append(result, dm.explain(rd))
+	}
+
+	return result, nil
+}
+
+// explain function returns the decisions which have led to the stored verdict of the resource: baseline, approvals
+// or the policy of its module
+func (dm *DecisionMaker) explain(rd *ResourceData) *Explanation {
+	explanation := &Explanation{Resource: rd, Allowed: rd.IsAllowed(), Removal: rd.Actions.Delete() || rd.Actions.Replace()}
+
+	if rd.Noise {
+		explanation.Reasons = append(explanation.Reasons, "only ignored attributes are changed ('ignore_rules'), so the resource is reported as unchanged")
+	}
+
+	if !explanation.Removal {
+		explanation.Reasons = append(explanation.Reasons, fmt.Sprintf("planned action is '%s', which is not checked by the gate", rd.ActionLabel()))
+
+		return explanation
+	}
+
+	if accepted := rd.AcceptedBy(); accepted != nil {
+		reason := fmt.Sprintf("the change is accepted by baseline file %s: %s", dm.config.BaselineFile, accepted.Justification)
+		if !accepted.Expires.IsZero() {
+			reason += fmt.Sprintf(" (expires on %s)", accepted.Expires.Format(time.DateOnly))
+		}
+		explanation.Reasons = append(explanation.Reasons, reason)
+
+		return explanation
+	}
+
+	if approval := rd.ApprovedBy(); approval != nil {
+		explanation.Reasons = append(explanation.Reasons, fmt.Sprintf("the removal is approved by %s: %s", approval.Approver, approval.Reason))
+
+		return explanation
+	}
+
+	policy := dm.config.PolicyFor(rd.Module)
+	for _, override := range policy.Overrides {
+		explanation.Reasons = append(explanation.Reasons, fmt.Sprintf("the policy of the module is overridden by %s", override))
+	}
+
+	// The location of config file parameter is shown, only if the policy is not overridden
+	located := func(name string, reason string) string {
+		if location, ok := dm.config.Locations[name]; ok && len(policy.Overrides) == 0 {
+			return fmt.Sprintf("%s ('%s' at %s)", reason, name, location)
+		}
+
+		return fmt.Sprintf("%s ('%s')", reason, name)
+	}
+
+	_, listed := policy.ExceptionalResources[rd.Type]
+	switch {
+	case policy.IsAllCriticalSpecified && listed:
+		explanation.Reasons = append(explanation.Reasons, located("critical_resources", "all resource types are critical"),
+			located("allowed_removals", fmt.Sprintf("'%s' is allowed for removal", rd.Type)))
+	case policy.IsAllCriticalSpecified:
+		explanation.Reasons = append(explanation.Reasons, located("critical_resources", "all resource types are critical"),
+			fmt.Sprintf("'%s' is not in 'allowed_removals'", rd.Type))
+	case listed:
+		explanation.Reasons = append(explanation.Reasons, located("critical_resources", fmt.Sprintf("'%s' is critical", rd.Type)))
+	default:
+		explanation.Reasons = append(explanation.Reasons, fmt.Sprintf("'%s' is not in 'critical_resources'", rd.Type))
+	}
+
+	return explanation
+}
@@ -0,0 +1,111 @@
+package processing
+
+import (
+	"testing"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+)
+
+type ExplainTestSuite struct {
+	suite.Suite
+
+	dm *DecisionMaker
+}
+
+func (ts *ExplainTestSuite) SetupTest() {
+	settings := &config.AppConfig{DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{"azurerm_key_vault": true}}}
+	settings.Locations = map[string]string{"critical_resources": "config.yml:5"}
+	settings.Approvals = []*config.Approval{{Module: "legacy", Approver: "alice", Reason: "Decommissioned"}}
+
+	ts.dm = NewDecisionMaker(settings)
+}
+
+// explain function explains the planned change of the resource in the plans with it only
+func (ts *ExplainTestSuite) explain(rd *ResourceData) *Explanation {
+	plans := &ConsolidatedJson{Updated: []*ResourceData{rd}}
+	if rd.Actions.Delete() {
+		plans = &ConsolidatedJson{Deleted: []*ResourceData{rd}}
+	}
+
+	ts.dm.Evaluate(plans)
+
+	explanations, err := ts.dm.Explain(plans, rd.Address)
+	assert.NoError(ts.T(), err)         //nolint:typecheck
+	assert.Len(ts.T(), explanations, 1) //nolint:typecheck
+
+	return explanations[0]
+}
+
+func (ts *ExplainTestSuite) TestCriticalRemovalBlocked() {
+	explanation := ts.explain(resource("azurerm_key_vault.main", "core", tfJson.ActionDelete))
+
+	assert.True(ts.T(), explanation.Removal)                                                                                      //nolint:typecheck
+	assert.False(ts.T(), explanation.Allowed)                                                                                     //nolint:typecheck
+	assert.Equal(ts.T(), []string{"'azurerm_key_vault' is critical ('critical_resources' at config.yml:5)"}, explanation.Reasons) //nolint:typecheck
+}
+
+func (ts *ExplainTestSuite) TestRemovalApproved() {
+	explanation := ts.explain(resource("azurerm_key_vault.main", "legacy", tfJson.ActionDelete))
+
+	assert.True(ts.T(), explanation.Allowed)                                                                //nolint:typecheck
+	assert.Equal(ts.T(), []string{"the removal is approved by alice: Decommissioned"}, explanation.Reasons) //nolint:typecheck
+}
+
+func (ts *ExplainTestSuite) TestUpdateNotChecked() {
+	explanation := ts.explain(resource("azurerm_key_vault.main", "core", tfJson.ActionUpdate))
+
+	assert.False(ts.T(), explanation.Removal)                   //nolint:typecheck
+	assert.True(ts.T(), explanation.Allowed)                    //nolint:typecheck
+	assert.Contains(ts.T(), explanation.Reasons[0], "'update'") //nolint:typecheck
+}
+
+func (ts *ExplainTestSuite) TestStoredVerdictExplained() {
+	blocked := resource("azurerm_key_vault.main", "core", tfJson.ActionDelete)
+	approved := resource("azurerm_key_vault.main", "legacy", tfJson.ActionDelete)
+	plans := &ConsolidatedJson{Deleted: []*ResourceData{blocked, approved}}
+
+	ts.dm.Evaluate(plans)
+
+	explanations, err := ts.dm.Explain(plans, "azurerm_key_vault.main")
+	assert.NoError(ts.T(), err)         //nolint:typecheck
+	assert.Len(ts.T(), explanations, 2) //nolint:typecheck
+
+	for _, explanation := range explanations {
+		assert.NotNil(ts.T(), explanation.Resource.Verdict)                         //nolint:typecheck
+		assert.Equal(ts.T(), explanation.Resource.IsAllowed(), explanation.Allowed) //nolint:typecheck
+	}
+	assert.False(ts.T(), explanations[0].Allowed) //nolint:typecheck
+	assert.True(ts.T(), explanations[1].Allowed)  //nolint:typecheck
+
+	explanations, err = ts.dm.Explain(plans, "azurerm_key_vault.other")
+	assert.NoError(ts.T(), err)        //nolint:typecheck
+	assert.Empty(ts.T(), explanations) //nolint:typecheck
+}
+
+// The verdict is not evaluated by Explain, so the plans which were not evaluated can't be explained
+func (ts *ExplainTestSuite) TestNotEvaluatedReported() {
+	removal := resource("azurerm_key_vault.main", "core", tfJson.ActionDelete)
+	plans := &ConsolidatedJson{Deleted: []*ResourceData{removal}}
+
+	_, err := ts.dm.Explain(plans, "azurerm_key_vault.main")
+	assert.ErrorContains(ts.T(), err, "not evaluated") //nolint:typecheck
+	assert.Nil(ts.T(), removal.Verdict)                //nolint:typecheck
+}
+
+func (ts *ExplainTestSuite) TestFindReplacedOnce() {
+	replaced := &ResourceData{Address: "azurerm_key_vault.main", Module: "core", Actions: tfJson.Actions{tfJson.ActionDelete, tfJson.ActionCreate}}
+	other := &ResourceData{Address: "azurerm_key_vault.main", Module: "extra", Actions: tfJson.Actions{tfJson.ActionUpdate}}
+	cj := &ConsolidatedJson{Created: []*ResourceData{replaced}, Deleted: []*ResourceData{replaced}, Updated: []*ResourceData{other}}
+
+	assert.Equal(ts.T(), []*ResourceData{replaced, other}, cj.Find("azurerm_key_vault.main")) //nolint:typecheck
+	assert.Empty(ts.T(), cj.Find("azurerm_key_vault.other"))                                  //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestExplain(t *testing.T) {
+	suite.Run(t, new(ExplainTestSuite))
+}
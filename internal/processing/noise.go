@@ -0,0 +1,38 @@
+package processing
+
+// IsNoise function checks if the planned change of the resource is the in-place update of ignored attributes only,
+// according to `ignore_rules` config file parameter and the ones of per-directory overrides. Such updates are reported as unchanged resources.
+// Without the decision maker nothing is noise
+func (dm *DecisionMaker) IsNoise(rd *ResourceData) bool {
+	if dm == nil || dm.config == nil || !rd.Actions.Update() || len(rd.Changes) == 0 {
+		return false
+	}
+
+	rules := dm.config.IgnoreRules
+	if len(dm.config.Overrides) > 0 {
+		rules = dm.config.PolicyFor(rd.Module).IgnoreRules
+	}
+
+	var ignored []string
+	for _, rule := range rules {
+		if rule.ResourceType == "*" || rule.ResourceType == rd.Type {
+			ignored = append(ignored, rule.Attributes...)
+		}
+	}
+
+	for _, change := range rd.Changes {
+		covered := false
+		for _, attrPath := range ignored {
+			if isAttributeCovered(attrPath, change.Path) {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,80 @@
+package processing
+
+import (
+	"testing"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+)
+
+type NoiseTestSuite struct {
+	suite.Suite
+
+	dm *DecisionMaker
+}
+
+func (ts *NoiseTestSuite) SetupTest() {
+	settings := &config.AppConfig{}
+	settings.IgnoreRules = []config.IgnoreRule{
+		{ResourceType: "*", Attributes: []string{"tags_all"}},
+		{ResourceType: "azurerm_storage_account", Attributes: []string{"etag"}},
+	}
+
+	ts.dm = NewDecisionMaker(settings)
+}
+
+func update(address string, resourceType string, before map[string]interface{}, after map[string]interface{}) *tfJson.ResourceChange {
+	return &tfJson.ResourceChange{Address: address, Type: resourceType, Change: &tfJson.Change{
+		Actions: tfJson.Actions{tfJson.ActionUpdate},
+		Before:  before,
+		After:   after,
+	}}
+}
+
+func (ts *NoiseTestSuite) TestUpdatesReclassified() {
+	data := new(ConsolidatedJson)
+	data.Parse("mod", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		update("azurerm_storage_account.a", "azurerm_storage_account",
+			map[string]interface{}{"etag": "1", "tags_all": map[string]interface{}{"env": "dev"}},
+			map[string]interface{}{"etag": "2", "tags_all": map[string]interface{}{"env": "prod"}}),
+		update("azurerm_key_vault.b", "azurerm_key_vault",
+			map[string]interface{}{"etag": "1"},
+			map[string]interface{}{"etag": "2"}),
+		update("azurerm_key_vault.c", "azurerm_key_vault",
+			map[string]interface{}{"sku": "standard", "tags_all": map[string]interface{}{"env": "dev"}},
+			map[string]interface{}{"sku": "premium", "tags_all": map[string]interface{}{"env": "prod"}}),
+	}}, ts.dm)
+
+	assert.Equal(ts.T(), 1, len(data.Unchanged))                                 //nolint:typecheck
+	assert.Equal(ts.T(), "azurerm_storage_account.a", data.Unchanged[0].Address) //nolint:typecheck
+	assert.True(ts.T(), data.Unchanged[0].Noise)                                 //nolint:typecheck
+	assert.Equal(ts.T(), 2, len(data.Updated))                                   //nolint:typecheck
+	assert.Equal(ts.T(), 1, data.SuppressedItems())                              //nolint:typecheck
+}
+
+func (ts *NoiseTestSuite) TestWithoutDecisionMaker() {
+	data := new(ConsolidatedJson)
+	data.Parse("mod", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		update("azurerm_storage_account.a", "azurerm_storage_account", map[string]interface{}{"etag": "1"}, map[string]interface{}{"etag": "2"}),
+	}}, nil)
+
+	assert.Equal(ts.T(), 1, len(data.Updated)) //nolint:typecheck
+}
+
+func (ts *NoiseTestSuite) TestReplaceNotReclassified() {
+	rd := &ResourceData{
+		Type:    "azurerm_storage_account",
+		Actions: tfJson.Actions{tfJson.ActionDelete, tfJson.ActionCreate},
+		Changes: []*AttributeChange{{Path: "etag"}},
+	}
+
+	assert.False(ts.T(), ts.dm.IsNoise(rd)) //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestNoise(t *testing.T) {
+	suite.Run(t, new(NoiseTestSuite))
+}
@@ -1,34 +1,52 @@
 package processing
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 
 	tfJson "github.com/hashicorp/terraform-json"
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	tgCacheFolderName = ".terragrunt-cache"
+)
+
 type processingRequest struct {
 	commandName string
 	planPath    string
-	parsedData  chan<- tfJson.Plan
+	module      string
+	parsedData  chan<- *parsedPlan
 	pool        chan int
 	notChDir    bool
 }
 
+type parsedPlan struct {
+	module string
+	plan   tfJson.Plan
+	err    error
+}
+
 // CollectBinaryData function does:
 // 1. searches all terraform generated binary plan files, with basename specified in `terraform_plan_file_basename`,
 // starting from root director specified in `terraform_plan_search_folder` config file parameter
 // 2. fills of `reportData` variable, by parsed terraform plan data, which further is going to be source of printed report
+// The decision maker detects updates of ignored attributes while parsing, it might be nil if there are no ignore rules
 // TODO: Implement test of this function to make sure that it works as expected
-func CollectBinaryData(searchFolder string, planBaseFileName string, cmdFullPathName string, notChDir bool, zeroFoundFail bool) *ConsolidatedJson {
-	foundPlanFiles := findAllTFPlanFiles(searchFolder, planBaseFileName)
+func CollectBinaryData(searchFolder string, planBaseFileName string, cmdFullPathName string, notChDir bool, zeroFoundFail bool, dm *DecisionMaker) (*ConsolidatedJson, error) {
+	foundPlanFiles, err := findAllTFPlanFiles(searchFolder, planBaseFileName)
+	if err != nil {
+		return nil, err
+	}
 
 	foundItems := len(foundPlanFiles)
 	log.WithFields(log.Fields{
@@ -38,54 +56,68 @@ func CollectBinaryData(searchFolder string, planBaseFileName string, cmdFullPath
 
 	reportData := new(ConsolidatedJson)
 
-	if foundItems > 0 {
-
-		if notChDir == false {
-			log.Debug("Checking if Terraform providers folder exists near with TF plan files in advance, 'not_use_chdir': false")
-
-			for _, absTFPlanFilePath := range foundPlanFiles {
-				if !TfProviderFolderExist(absTFPlanFilePath) {
-					log.Fatalf("Terraform providers folder (.terraform/providers) was not found inside of: %s ,'not_use_chdir': false", absTFPlanFilePath)
-				}
-			}
+	if foundItems == 0 {
+		if zeroFoundFail {
+			return nil, fmt.Errorf("could not find TF-plan file, while 'zero-plan-fail' cli arg specified")
 		}
 
-		pool := make(chan int, runtime.GOMAXPROCS(0))
-		dataPipe := make(chan tfJson.Plan, runtime.GOMAXPROCS(0))
+		return reportData, nil
+	}
+
+	if notChDir == false {
+		log.Debug("Checking if Terraform providers folder exists near with TF plan files in advance, 'not_use_chdir': false")
 
 		for _, absTFPlanFilePath := range foundPlanFiles {
-			pr := &processingRequest{
-				commandName: cmdFullPathName,
-				planPath:    absTFPlanFilePath,
-				parsedData:  dataPipe,
-				pool:        pool,
-				notChDir:    notChDir,
+			if !TfProviderFolderExist(filepath.Dir(absTFPlanFilePath)) {
+				return nil, fmt.Errorf("terraform providers folder (.terraform/providers) was not found inside of: %s ,'not_use_chdir': false", absTFPlanFilePath)
 			}
+		}
+	}
 
-			go tfPlanReader(pr) //Async TF plan reader
+	pool := make(chan int, runtime.GOMAXPROCS(0))
+	dataPipe := make(chan *parsedPlan, runtime.GOMAXPROCS(0))
+
+	for _, absTFPlanFilePath := range foundPlanFiles {
+		pr := &processingRequest{
+			commandName: cmdFullPathName,
+			planPath:    absTFPlanFilePath,
+			module:      moduleName(searchFolder, absTFPlanFilePath),
+			parsedData:  dataPipe,
+			pool:        pool,
+			notChDir:    notChDir,
 		}
 
-		//Parsing of read data
-		log.Debug("Waiting of data from read TF plan files for processing")
-		for item := 0; item < foundItems; item++ {
-			tfPlan := <-dataPipe
+		go tfPlanReader(pr) //Async TF plan reader
+	}
 
-			reportData.Parse(&tfPlan)
-		}
-	} else {
-		if zeroFoundFail {
-			log.Fatal("Could not find TF-plan file, while 'zero-plan-fail' cli arg specified")
+	//Parsing of read data. All readers are waited for, even if some of them have failed
+	log.Debug("Waiting of data from read TF plan files for processing")
+	var errs []error
+	for item := 0; item < foundItems; item++ {
+		parsed := <-dataPipe
+		if parsed.err != nil {
+			errs = append(errs, parsed.err)
+			continue
 		}
+
+		reportData.Parse(parsed.module, &parsed.plan, dm)
 	}
 
-	return reportData
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return reportData, nil
 }
 
 // TODO: Implement test of this function to make sure that it works as expected
-func findAllTFPlanFiles(searchFolder string, fileBasename string) []string {
+func findAllTFPlanFiles(searchFolder string, fileBasename string) ([]string, error) {
 	var result []string
 
 	if err := filepath.WalkDir(searchFolder, func(currentPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
 
 		if d.Type().IsRegular() {
 			pathElements := strings.Split(currentPath, string(os.PathSeparator))
@@ -99,10 +131,50 @@ func findAllTFPlanFiles(searchFolder string, fileBasename string) []string {
 
 		return nil
 	}); err != nil {
-		log.Fatalf("During directory tree walking the error happened: %s", err)
+		return nil, fmt.Errorf("during directory tree walking the error happened: %w", err)
 	}
 
-	return result
+	return result, nil
+}
+
+// FindPlanModules function returns sorted names of modules, whose TF plan files are found in the search folder
+func FindPlanModules(searchFolder string, planBaseFileName string) ([]string, error) {
+	planFiles, err := findAllTFPlanFiles(searchFolder, planBaseFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []string
+	for _, planPath := range planFiles {
+		if module := moduleName(searchFolder, planPath); !slices.Contains(modules, module) {
+			modules = append(modules, module)
+		}
+	}
+	slices.Sort(modules)
+
+	return modules, nil
+}
+
+// moduleName function returns the name of terragrunt/terraform module which the TF plan file belongs to. It's the path of
+// folder containing the plan file, relative to the search folder, with terragrunt cache part cut off
+func moduleName(searchFolder string, planPath string) string {
+	planDir := filepath.Dir(planPath)
+
+	relPath, err := filepath.Rel(searchFolder, planDir)
+	if err != nil {
+		relPath = planDir
+	}
+
+	pathElements := strings.Split(relPath, string(os.PathSeparator))
+	if i := slices.Index(pathElements, tgCacheFolderName); i > -1 {
+		pathElements = pathElements[:i]
+	}
+
+	if len(pathElements) == 0 {
+		return "."
+	}
+
+	return filepath.Join(pathElements...)
 }
 
 func tfPlanReader(pr *processingRequest) {
@@ -112,10 +184,26 @@ func tfPlanReader(pr *processingRequest) {
 	planFileContext.Debug("Waiting of green light in process pool")
 	pr.pool <- 1
 	planFileContext.Debug("Green light has been acquired")
+	//Return back capacity to the pool
+	defer func() { <-pr.pool }()
+
+	plan, err := readPlan(pr, planFileContext)
+	if err != nil {
+		pr.parsedData <- &parsedPlan{module: pr.module, err: fmt.Errorf("%s: %w", pr.planPath, err)}
 
+		return
+	}
+
+	pr.parsedData <- &parsedPlan{module: pr.module, plan: *plan}
+
+	planFileContext.Print("Parsing finished")
+}
+
+// readPlan function runs `terraform show` over the TF plan file of the request and decodes its output
+func readPlan(pr *processingRequest, planFileContext *log.Entry) (*tfJson.Plan, error) {
 	cmdResolvedPath, err := exec.LookPath(pr.commandName)
 	if err != nil {
-		planFileContext.Fatalf("Could not find the command file: %s", pr.commandName)
+		return nil, fmt.Errorf("could not find the command file: %s", pr.commandName)
 	}
 
 	var auxCmdArgs string
@@ -140,29 +228,53 @@ func tfPlanReader(pr *processingRequest) {
 	cmdContext.Debug("Command launching")
 
 	cmd := exec.Command(cmdResolvedPath, strings.Split(auxCmdArgs, " ")...)
-	var outputPlan strings.Builder
 	var tfErr strings.Builder
-
-	cmd.Stdout = &outputPlan
 	cmd.Stderr = &tfErr
 
-	err = cmd.Run()
+	// The output is decoded while it's streamed, instead of being buffered, since plans of big modules might take
+	// hundreds of MB
+	outputPlan, err := cmd.StdoutPipe()
 	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("during execution the error happened: %w", err)
+	}
+
+	tfJsonPlan, decodeErr := DecodePlan(outputPlan)
+	//The rest of output is drained, otherwise the command might be blocked on writing and never exit
+	if _, err := io.Copy(io.Discard, outputPlan); err != nil {
+		cmdContext.Debugf("Could not drain command output: %s", err)
+	}
+
+	if err := cmd.Wait(); err != nil {
 		cmdContext.Debugf("Command stderr output:\n%s", tfErr.String())
-		cmdContext.Fatalf("During execution the error happened: %s", err)
+
+		return nil, fmt.Errorf("during execution the error happened: %w", err)
 	}
 
-	var tfJsonPlan tfJson.Plan
-	err = tfJsonPlan.UnmarshalJSON([]byte(outputPlan.String()))
-	if err != nil {
-		planFileContext.Fatalf("Could not unmarshal: %s", err)
+	if decodeErr != nil {
+		return nil, fmt.Errorf("could not unmarshal: %w", decodeErr)
 	}
 
 	planFileContext.Debugf("Harvested records: %v", len(tfJsonPlan.ResourceChanges))
 
-	pr.parsedData <- tfJsonPlan
+	return tfJsonPlan, nil
+}
+
+// ReadPlanJson function reads the plan already converted to JSON, e.g. by `terraform show -json` step of Atlantis
+func ReadPlanJson(fileName string) (*tfJson.Plan, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
 
-	planFileContext.Print("Parsing finished")
-	//Return back capacity to the pool
-	<-pr.pool
+	plan, err := DecodePlan(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal %s: %w", fileName, err)
+	}
+
+	return plan, nil
 }
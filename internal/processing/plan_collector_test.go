@@ -0,0 +1,123 @@
+package processing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+)
+
+const collectorTestPlan = `{"format_version": "1.2", "resource_changes": [
+	{"address": "azurerm_key_vault.main", "type": "azurerm_key_vault", "name": "main", "change": {"actions": ["delete"]}}
+]}`
+
+type PlanCollectorTestSuite struct {
+	suite.Suite
+
+	tmpDir    string
+	terraform string
+}
+
+// SetupTest function creates the fake terraform binary, which prints the same plan JSON for any TF plan file
+func (ts *PlanCollectorTestSuite) SetupTest() {
+	ts.tmpDir = ts.T().TempDir()
+
+	planJson := filepath.Join(ts.tmpDir, "plan.json")
+	ts.terraform = filepath.Join(ts.tmpDir, "terraform")
+	if err := os.WriteFile(planJson, []byte(collectorTestPlan), 0644); err != nil {
+		ts.T().Fatalf("Could not create file: %s", planJson) //nolint:typecheck
+	}
+	if err := os.WriteFile(ts.terraform, []byte("#!/bin/sh\ncat "+planJson+"\n"), 0755); err != nil {
+		ts.T().Fatalf("Could not create file: %s", ts.terraform) //nolint:typecheck
+	}
+}
+
+func (ts *PlanCollectorTestSuite) createPlan(module string, withProviders bool) string {
+	folder := filepath.Join(ts.tmpDir, "plans", module)
+	if withProviders {
+		folder = filepath.Join(folder, tfProvidersFolderName)
+	}
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		ts.T().Fatalf("Could not create folder: %s", folder) //nolint:typecheck
+	}
+
+	planPath := filepath.Join(ts.tmpDir, "plans", module, "plan.bin")
+	if err := os.WriteFile(planPath, nil, 0644); err != nil {
+		ts.T().Fatalf("Could not create file: %s", planPath) //nolint:typecheck
+	}
+
+	return planPath
+}
+
+func (ts *PlanCollectorTestSuite) collect() (*ConsolidatedJson, error) {
+	return CollectBinaryData(filepath.Join(ts.tmpDir, "plans"), "plan.bin", ts.terraform, false, false, nil)
+}
+
+// The providers folder is looked for next to the TF plan file, not inside of its path, which is never a folder
+func (ts *PlanCollectorTestSuite) TestProvidersFolderNearPlan() {
+	planPath := ts.createPlan("key-vaults", true)
+
+	assert.False(ts.T(), TfProviderFolderExist(planPath))              //nolint:typecheck
+	assert.True(ts.T(), TfProviderFolderExist(filepath.Dir(planPath))) //nolint:typecheck
+
+	data, err := ts.collect()
+	assert.NoError(ts.T(), err)                                             //nolint:typecheck
+	assert.Equal(ts.T(), []string{"key-vaults"}, data.Modules)              //nolint:typecheck
+	assert.Equal(ts.T(), "azurerm_key_vault.main", data.Deleted[0].Address) //nolint:typecheck
+}
+
+func (ts *PlanCollectorTestSuite) TestProvidersFolderMissing() {
+	ts.createPlan("key-vaults", true)
+	ts.createPlan("rgs", false)
+
+	_, err := ts.collect()
+	assert.ErrorContains(ts.T(), err, "providers folder") //nolint:typecheck
+	assert.ErrorContains(ts.T(), err, "rgs")              //nolint:typecheck
+}
+
+// Plans of matrix environments are evaluated with the overrides of their own folders, keyed by the module names
+// relative to the environment folder rather than to the search folder of the config
+func (ts *PlanCollectorTestSuite) TestEnvironmentOverrides() {
+	ts.createPlan("prod/key-vaults", true)
+	ts.createPlan("dev/key-vaults", true)
+
+	override := filepath.Join(ts.tmpDir, "plans", "prod", "key-vaults", config.OverrideFileName)
+	if err := os.WriteFile(override, []byte("critical_resources: [azurerm_key_vault]\n"), 0644); err != nil {
+		ts.T().Fatalf("Could not create file: %s", override) //nolint:typecheck
+	}
+
+	settings := &config.AppConfig{
+		ConfigFile:  config.ConfigFile{SearchFolder: filepath.Join(ts.tmpDir, "plans")},
+		DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{}},
+	}
+
+	blocked := make(map[string]bool)
+	for _, environment := range []string{"prod", "dev"} {
+		envSettings, err := settings.ForSearchFolder(filepath.Join(ts.tmpDir, "plans", environment))
+		if err != nil {
+			ts.T().Fatalf("Could not load overrides of environment: %s", err) //nolint:typecheck
+		}
+
+		dm := NewDecisionMaker(envSettings)
+		data, err := CollectBinaryData(envSettings.SearchFolder, "plan.bin", ts.terraform, false, false, dm)
+		if err != nil {
+			ts.T().Fatalf("Could not collect plans of environment: %s", err) //nolint:typecheck
+		}
+		assert.Equal(ts.T(), []string{"key-vaults"}, data.Modules) //nolint:typecheck
+		dm.Evaluate(data)
+
+		blocked[environment] = data.CriticalRemovalsFound()
+	}
+
+	assert.Equal(ts.T(), map[string]bool{"prod": true, "dev": false}, blocked) //nolint:typecheck
+	assert.Empty(ts.T(), settings.Overrides)                                   //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestPlanCollector(t *testing.T) {
+	suite.Run(t, new(PlanCollectorTestSuite))
+}
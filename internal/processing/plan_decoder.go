@@ -0,0 +1,192 @@
+package processing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	tfJson "github.com/hashicorp/terraform-json"
+)
+
+// DecodePlan function reads the plan JSON (the output of `terraform show -json`) from the stream token by token and
+// keeps only the fields needed by the report: format and terraform versions, timestamp, resource and output changes.
+// The rest of them (prior state, configuration, planned values, etc.), which are the biggest part of the plan, are
+// skipped without being buffered, so the memory used doesn't depend on their size. The resource changes are decoded
+// one by one as well
+func DecodePlan(r io.Reader) (*tfJson.Plan, error) {
+	return decodePlan(json.NewDecoder(r))
+}
+
+// DecodePlans function decodes the stream, which is either a single plan JSON put to the result with `module` key, or
+// the object with many of them keyed by module names: {"plans": {"<module>": <plan JSON>, ...}}. Every plan is
+// decoded the same way as DecodePlan does
+func DecodePlans(r io.Reader, module string) (map[string]*tfJson.Plan, error) {
+	decoder := json.NewDecoder(r)
+	plan := new(tfJson.Plan)
+
+	var plans map[string]*tfJson.Plan
+	if err := decodeObject(decoder, func(key string) error {
+		if key != "plans" {
+			return decodePlanField(decoder, key, plan)
+		}
+
+		plans = make(map[string]*tfJson.Plan)
+
+		return decodeObject(decoder, func(planModule string) error {
+			modulePlan, err := decodePlan(decoder)
+			if err != nil {
+				return fmt.Errorf("plan of module '%s': %w", planModule, err)
+			}
+
+			plans[planModule] = modulePlan
+
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	if plans != nil {
+		if len(plans) == 0 {
+			return nil, errors.New("'plans' object is empty")
+		}
+
+		return plans, nil
+	}
+
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+
+	return map[string]*tfJson.Plan{module: plan}, nil
+}
+
+func decodePlan(decoder *json.Decoder) (*tfJson.Plan, error) {
+	plan := new(tfJson.Plan)
+	if err := decodeObject(decoder, func(key string) error { return decodePlanField(decoder, key, plan) }); err != nil {
+		return nil, err
+	}
+
+	return plan, plan.Validate()
+}
+
+// decodePlanField function decodes the value of the plan field, which is needed by the report, and skips the rest
+func decodePlanField(decoder *json.Decoder, key string, plan *tfJson.Plan) error {
+	var field interface{}
+	switch key {
+	case "format_version":
+		field = &plan.FormatVersion
+	case "terraform_version":
+		field = &plan.TerraformVersion
+	case "timestamp":
+		field = &plan.Timestamp
+	case "output_changes":
+		field = &plan.OutputChanges
+	case "resource_changes":
+		changes, err := decodeResourceChanges(decoder)
+		if err != nil {
+			return fmt.Errorf("could not decode '%s': %w", key, err)
+		}
+		plan.ResourceChanges = changes
+
+		return nil
+	default:
+		if err := skipValue(decoder); err != nil {
+			return fmt.Errorf("could not skip '%s': %w", key, err)
+		}
+
+		return nil
+	}
+
+	if err := decoder.Decode(field); err != nil {
+		return fmt.Errorf("could not decode '%s': %w", key, err)
+	}
+
+	return nil
+}
+
+// decodeObject function reads the object from the stream, the value of every key is read by `value` callback
+func decodeObject(decoder *json.Decoder, value func(key string) error) error {
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		if err := value(token.(string)); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(decoder, '}')
+}
+
+// decodeResourceChanges function decodes the array of resource changes element by element
+func decodeResourceChanges(decoder *json.Decoder) ([]*tfJson.ResourceChange, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if token == nil {
+		return nil, nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("expected array, got %v", token)
+	}
+
+	var result []*tfJson.ResourceChange
+	for decoder.More() {
+		change := new(tfJson.ResourceChange)
+		if err := decoder.Decode(change); err != nil {
+			return nil, err
+		}
+
+		result = append(result, change)
+	}
+
+	return result, expectDelim(decoder, ']')
+}
+
+// skipValue function reads the next value of the stream till its end, token by token, whatever its size is
+func skipValue(decoder *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		if delim, ok := token.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			default:
+				depth--
+			}
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func expectDelim(decoder *json.Decoder, expected json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return fmt.Errorf("expected '%v', got %v", expected, token)
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package processing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PlanDecoderTestSuite struct {
+	suite.Suite
+}
+
+// generatePlan function generates the plan JSON with the given amount of resource changes. Prior state, planned
+// values and configuration are filled by the same resources, like terraform does, so they take the most of the plan
+func generatePlan(amount int) []byte {
+	var changes, resources, configs []map[string]interface{}
+	for i := 0; i < amount; i++ {
+		address := fmt.Sprintf("azurerm_storage_account.sa[%d]", i)
+		values := map[string]interface{}{
+			"name":     fmt.Sprintf("storage%d", i),
+			"location": "westeurope",
+			"tags":     map[string]interface{}{"owner": "team", "index": i},
+			"blob":     map[string]interface{}{"versioning": true, "retention": []int{7, 30, 90}},
+		}
+
+		actions := []string{"no-op"}
+		switch i % 4 {
+		case 1:
+			actions = []string{"update"}
+		case 2:
+			actions = []string{"delete", "create"}
+		case 3:
+			actions = []string{"delete"}
+		}
+
+		changes = append(changes, map[string]interface{}{
+			"address": address,
+			"mode":    "managed",
+			"type":    "azurerm_storage_account",
+			"name":    "sa",
+			"index":   i,
+			"change":  map[string]interface{}{"actions": actions, "before": values, "after": values},
+		})
+		resources = append(resources, map[string]interface{}{
+			"address": address,
+			"mode":    "managed",
+			"type":    "azurerm_storage_account",
+			"name":    "sa",
+			"index":   i,
+			"values":  values,
+		})
+		configs = append(configs, map[string]interface{}{
+			"address":     address,
+			"expressions": map[string]interface{}{"name": map[string]interface{}{"constant_value": values["name"]}},
+		})
+	}
+
+	content, _ := json.Marshal(map[string]interface{}{
+		"format_version":    "1.2",
+		"terraform_version": "1.9.5",
+		"planned_values":    map[string]interface{}{"root_module": map[string]interface{}{"resources": resources}},
+		"resource_changes":  changes,
+		"output_changes": map[string]interface{}{
+			"id": map[string]interface{}{"actions": []string{"create"}, "after_unknown": true},
+		},
+		"prior_state":   map[string]interface{}{"format_version": "1.0", "values": map[string]interface{}{"root_module": map[string]interface{}{"resources": resources}}},
+		"configuration": map[string]interface{}{"root_module": map[string]interface{}{"resources": configs}},
+		"timestamp":     "2026-10-19T10:00:00Z",
+	})
+
+	return content
+}
+
+func (ts *PlanDecoderTestSuite) TestNeededFieldsDecoded() {
+	content := generatePlan(20)
+
+	expected := new(tfJson.Plan)
+	assert.NoError(ts.T(), expected.UnmarshalJSON(content)) //nolint:typecheck
+
+	plan, err := DecodePlan(bytes.NewReader(content))
+	assert.NoError(ts.T(), err) //nolint:typecheck
+
+	assert.Equal(ts.T(), expected.FormatVersion, plan.FormatVersion)       //nolint:typecheck
+	assert.Equal(ts.T(), expected.TerraformVersion, plan.TerraformVersion) //nolint:typecheck
+	assert.Equal(ts.T(), expected.Timestamp, plan.Timestamp)               //nolint:typecheck
+	assert.Equal(ts.T(), expected.ResourceChanges, plan.ResourceChanges)   //nolint:typecheck
+	assert.Equal(ts.T(), expected.OutputChanges, plan.OutputChanges)       //nolint:typecheck
+	assert.Nil(ts.T(), plan.PriorState)                                    //nolint:typecheck
+	assert.Nil(ts.T(), plan.Config)                                        //nolint:typecheck
+	assert.Nil(ts.T(), plan.PlannedValues)                                 //nolint:typecheck
+
+	data := new(ConsolidatedJson)
+	data.Parse("sa", plan, nil)
+	assert.Equal(ts.T(), 10, len(data.Deleted)) //nolint:typecheck
+}
+
+func (ts *PlanDecoderTestSuite) TestEmptyPlan() {
+	plan, err := DecodePlan(bytes.NewReader([]byte(`{"format_version": "1.2", "resource_changes": null, "applyable": false}`)))
+	assert.NoError(ts.T(), err)                     //nolint:typecheck
+	assert.Empty(ts.T(), plan.ResourceChanges)      //nolint:typecheck
+	assert.Equal(ts.T(), "1.2", plan.FormatVersion) //nolint:typecheck
+}
+
+func (ts *PlanDecoderTestSuite) TestInvalidPlan() {
+	for _, content := range []string{
+		``,
+		`[]`,
+		`{"format_version": "2.0"}`,
+		`{"terraform_version": "1.9.5"}`,
+		`{"format_version": "1.2", "resource_changes": {}}`,
+		`{"format_version": "1.2", "prior_state": {"values": [`,
+		string(generatePlan(3)[:500]),
+	} {
+		_, err := DecodePlan(bytes.NewReader([]byte(content)))
+		assert.Error(ts.T(), err, content) //nolint:typecheck
+	}
+}
+
+func (ts *PlanDecoderTestSuite) TestSeveralPlans() {
+	plans, err := DecodePlans(bytes.NewReader([]byte(`{"plans": {
+		"app": {"format_version": "1.2", "resource_changes": [{"address": "null_resource.a", "change": {"actions": ["delete"]}}]},
+		"db": {"format_version": "1.2", "prior_state": {"values": {}}}
+	}}`)), "plan")
+	assert.NoError(ts.T(), err)                                                      //nolint:typecheck
+	assert.Len(ts.T(), plans, 2)                                                     //nolint:typecheck
+	assert.Equal(ts.T(), "null_resource.a", plans["app"].ResourceChanges[0].Address) //nolint:typecheck
+	assert.Empty(ts.T(), plans["db"].ResourceChanges)                                //nolint:typecheck
+
+	plans, err = DecodePlans(bytes.NewReader(generatePlan(4)), "plan")
+	assert.NoError(ts.T(), err)                          //nolint:typecheck
+	assert.Len(ts.T(), plans["plan"].ResourceChanges, 4) //nolint:typecheck
+
+	for _, content := range []string{
+		`{"plans": {}}`,
+		`{"plans": {"app": {"format_version": "2.0"}}}`,
+		`{"terraform_version": "1.9.5"}`,
+		`[]`,
+	} {
+		_, err := DecodePlans(bytes.NewReader([]byte(content)), "plan")
+		assert.Error(ts.T(), err, content) //nolint:typecheck
+	}
+}
+
+// Entry point for the test suite
+func TestPlanDecoder(t *testing.T) {
+	suite.Run(t, new(PlanDecoderTestSuite))
+}
+
+// BenchmarkUnmarshalPlan function measures the previous way of reading of the plan: buffering of the whole output
+// and unmarshalling of the full plan
+func BenchmarkUnmarshalPlan(b *testing.B) {
+	content := generatePlan(2000)
+	b.SetBytes(int64(len(content)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		var output bytes.Buffer
+		if _, err := output.ReadFrom(bytes.NewReader(content)); err != nil {
+			b.Fatal(err)
+		}
+
+		plan := new(tfJson.Plan)
+		if err := plan.UnmarshalJSON(output.Bytes()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDecodePlan function measures the streaming decoding of the same plan
+func BenchmarkDecodePlan(b *testing.B) {
+	content := generatePlan(2000)
+	b.SetBytes(int64(len(content)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodePlan(bytes.NewReader(content)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
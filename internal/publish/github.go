@@ -0,0 +1,211 @@
+package publish
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	gitHubDefaultApiUrl = "https://api.github.com"
+	gitHubPageSize      = 100
+	gitHubActionsLogin  = "github-actions[bot]" //Author of comments posted with GITHUB_TOKEN of GitHub Actions
+)
+
+var (
+	gitHubPullRequestRef = regexp.MustCompile(`^refs/pull/(\d+)/`)
+)
+
+// GitHubPublisher creates or updates the single (sticky) comment of the report in the pull request
+type GitHubPublisher struct {
+	ApiUrl      string
+	Repository  string //Repository in form `owner/name`
+	PullRequest int
+	Token       string
+
+	client *apiClient
+}
+
+type gitHubComment struct {
+	Id   int64      `json:"id"`
+	Body string     `json:"body"`
+	User gitHubUser `json:"user"`
+}
+
+type gitHubUser struct {
+	Login string `json:"login"`
+}
+
+// NewGitHubPublisher function creates the publisher, taking not specified parameters from GitHub Actions environment
+// variables: GITHUB_API_URL, GITHUB_REPOSITORY, GITHUB_TOKEN, and the pull request number from GITHUB_EVENT_PATH or GITHUB_REF
+func NewGitHubPublisher(repository string, pullRequest int, token string) (*GitHubPublisher, error) {
+	p := &GitHubPublisher{
+		ApiUrl:      strings.TrimSuffix(os.Getenv("GITHUB_API_URL"), "/"),
+		Repository:  repository,
+		PullRequest: pullRequest,
+		Token:       token,
+	}
+
+	if len(p.ApiUrl) == 0 {
+		p.ApiUrl = gitHubDefaultApiUrl
+	}
+
+	if len(p.Repository) == 0 {
+		p.Repository = os.Getenv("GITHUB_REPOSITORY")
+	}
+
+	if len(p.Token) == 0 {
+		p.Token = os.Getenv("GITHUB_TOKEN")
+	}
+
+	if p.PullRequest == 0 {
+		p.PullRequest = gitHubPullRequestFromEnv()
+	}
+
+	if err := errors.Join(
+		checkIfSpecified(p.Repository, "GitHub repository (--github-repository or GITHUB_REPOSITORY)"),
+		checkIfSpecified(p.Token, "GitHub token (--github-token or GITHUB_TOKEN)"),
+		func() error {
+			if p.PullRequest <= 0 {
+				return errors.New("GitHub pull request number (--github-pull-request) must be specified, it could not be detected from the environment")
+			}
+
+			return nil
+		}(),
+	); err != nil {
+		return nil, err
+	}
+
+	p.client = newApiClient(map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"Authorization":        "Bearer " + p.Token,
+		"X-GitHub-Api-Version": "2022-11-28",
+	})
+
+	return p, nil
+}
+
+func (p *GitHubPublisher) Name() string {
+	return "github"
+}
+
+// Publish function updates the comment posted earlier (found by the hidden marker), or creates the new one
+func (p *GitHubPublisher) Publish(body string) error {
+	publishContext := log.WithFields(log.Fields{
+		"repository":   p.Repository,
+		"pull_request": p.PullRequest,
+	})
+
+	existing, err := p.findComment()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]string{"body": withMarker(body)}
+
+	if existing != nil {
+		publishContext.WithField("comment_id", existing.Id).Info("Updating of the existing pull request comment")
+
+		_, err = p.client.do(http.MethodPatch, fmt.Sprintf("%s/repos/%s/issues/comments/%d", p.ApiUrl, p.Repository, existing.Id), payload, nil)
+
+		return err
+	}
+
+	publishContext.Info("Creating of the new pull request comment")
+
+	_, err = p.client.do(http.MethodPost, fmt.Sprintf("%s/repos/%s/issues/%d/comments", p.ApiUrl, p.Repository, p.PullRequest), payload, nil)
+
+	return err
+}
+
+// findComment function returns the comment with the marker posted by the authenticated user. Comments of other users
+// are skipped even if they contain the marker, so nobody else could get their comment updated with the report
+func (p *GitHubPublisher) findComment() (*gitHubComment, error) {
+	login, err := p.login()
+	if err != nil {
+		return nil, err
+	}
+
+	for page := 1; ; page++ {
+		var comments []*gitHubComment
+
+		url := fmt.Sprintf("%s/repos/%s/issues/%d/comments?per_page=%d&page=%d", p.ApiUrl, p.Repository, p.PullRequest, gitHubPageSize, page)
+		if _, err := p.client.do(http.MethodGet, url, nil, &comments); err != nil {
+			return nil, err
+		}
+
+		for _, comment := range comments {
+			if hasMarker(comment.Body) && strings.EqualFold(comment.User.Login, login) {
+				return comment, nil
+			}
+		}
+
+		if len(comments) < gitHubPageSize {
+			return nil, nil
+		}
+	}
+}
+
+// login function returns the login of the authenticated user. GITHUB_TOKEN of GitHub Actions is not allowed to request
+// it, the comments are posted by the bot of GitHub Actions then
+func (p *GitHubPublisher) login() (string, error) {
+	var user gitHubUser
+
+	if _, err := p.client.do(http.MethodGet, p.ApiUrl+"/user", nil, &user); err != nil {
+		if os.Getenv("GITHUB_ACTIONS") != "true" {
+			return "", fmt.Errorf("could not get the authenticated GitHub user: %w", err)
+		}
+
+		log.WithError(err).Debugf("Could not get the authenticated GitHub user, comments of '%s' are looked for", gitHubActionsLogin)
+
+		return gitHubActionsLogin, nil
+	}
+
+	return user.Login, nil
+}
+
+// gitHubPullRequestFromEnv function detects the pull request number from the event payload or the ref of GitHub Actions run
+func gitHubPullRequestFromEnv() int {
+	if eventPath := os.Getenv("GITHUB_EVENT_PATH"); len(eventPath) > 0 {
+		if content, err := os.ReadFile(eventPath); err == nil {
+			var event struct {
+				Number      int `json:"number"`
+				PullRequest struct {
+					Number int `json:"number"`
+				} `json:"pull_request"`
+			}
+
+			if json.Unmarshal(content, &event) == nil {
+				if event.PullRequest.Number > 0 {
+					return event.PullRequest.Number
+				}
+				if event.Number > 0 {
+					return event.Number
+				}
+			}
+		}
+	}
+
+	if matches := gitHubPullRequestRef.FindStringSubmatch(os.Getenv("GITHUB_REF")); matches != nil {
+		number, _ := strconv.Atoi(matches[1])
+
+		return number
+	}
+
+	return 0
+}
+
+func checkIfSpecified(value string, name string) error {
+	if len(value) == 0 {
+		return fmt.Errorf("%s must be specified", name)
+	}
+
+	return nil
+}
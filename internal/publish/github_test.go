@@ -0,0 +1,181 @@
+package publish
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// gitHubMock is the local stand-in of GitHub REST API, which keeps comments of the single pull request
+type gitHubMock struct {
+	login    string //Login of the authenticated user, the user endpoint is forbidden if it's empty, like for GITHUB_TOKEN
+	comments []*gitHubComment
+	requests []string
+}
+
+func (m *gitHubMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.requests = append(m.requests, r.Method+" "+r.URL.Path)
+
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload gitHubComment
+	_ = json.NewDecoder(r.Body).Decode(&payload)
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/user":
+		if len(m.login) == 0 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(gitHubUser{Login: m.login})
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/issues/7/comments":
+		var page int
+		fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+
+		from := min((page-1)*gitHubPageSize, len(m.comments))
+		to := min(page*gitHubPageSize, len(m.comments))
+		_ = json.NewEncoder(w).Encode(m.comments[from:to])
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/issues/7/comments":
+		payload.Id = int64(len(m.comments) + 1)
+		payload.User.Login = m.login
+		if len(m.login) == 0 {
+			payload.User.Login = gitHubActionsLogin
+		}
+		m.comments = append(m.comments, &payload)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(payload)
+	case r.Method == http.MethodPatch:
+		for _, comment := range m.comments {
+			if r.URL.Path == fmt.Sprintf("/repos/owner/repo/issues/comments/%d", comment.Id) {
+				comment.Body = payload.Body
+				_ = json.NewEncoder(w).Encode(comment)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+type GitHubPublisherTestSuite struct {
+	suite.Suite
+
+	mock   *gitHubMock
+	server *httptest.Server
+}
+
+func (ts *GitHubPublisherTestSuite) SetupTest() {
+	ts.mock = &gitHubMock{login: "reporter"}
+	ts.server = httptest.NewServer(ts.mock)
+
+	ts.T().Setenv("GITHUB_API_URL", ts.server.URL) //nolint:typecheck
+	ts.T().Setenv("GITHUB_ACTIONS", "")            //nolint:typecheck
+}
+
+func (ts *GitHubPublisherTestSuite) TearDownTest() {
+	ts.server.Close()
+}
+
+func (ts *GitHubPublisherTestSuite) TestCommentCreatedThenUpdated() {
+	for i := 0; i < gitHubPageSize+5; i++ {
+		ts.mock.comments = append(ts.mock.comments, &gitHubComment{Id: int64(i + 1), Body: "LGTM"})
+	}
+
+	publisher, err := NewGitHubPublisher("owner/repo", 7, "secret")
+	assert.Nil(ts.T(), err) //nolint:typecheck
+
+	assert.Nil(ts.T(), publisher.Publish("first report"))                                     //nolint:typecheck
+	assert.Len(ts.T(), ts.mock.comments, gitHubPageSize+6)                                    //nolint:typecheck
+	assert.Equal(ts.T(), withMarker("first report"), ts.mock.comments[gitHubPageSize+5].Body) //nolint:typecheck
+
+	assert.Nil(ts.T(), publisher.Publish("second report"))                                                                 //nolint:typecheck
+	assert.Len(ts.T(), ts.mock.comments, gitHubPageSize+6)                                                                 //nolint:typecheck
+	assert.Equal(ts.T(), withMarker("second report"), ts.mock.comments[gitHubPageSize+5].Body)                             //nolint:typecheck
+	assert.Contains(ts.T(), ts.mock.requests, fmt.Sprintf("PATCH /repos/owner/repo/issues/comments/%d", gitHubPageSize+6)) //nolint:typecheck
+}
+
+// Comments of other users are never updated, even if they contain the marker
+func (ts *GitHubPublisherTestSuite) TestForeignCommentSkipped() {
+	ts.mock.comments = append(ts.mock.comments, &gitHubComment{Id: 1, Body: withMarker("forged"), User: gitHubUser{Login: "intruder"}})
+
+	publisher, err := NewGitHubPublisher("owner/repo", 7, "secret")
+	assert.Nil(ts.T(), err) //nolint:typecheck
+
+	assert.Nil(ts.T(), publisher.Publish("report"))                                           //nolint:typecheck
+	assert.Len(ts.T(), ts.mock.comments, 2)                                                   //nolint:typecheck
+	assert.Equal(ts.T(), withMarker("forged"), ts.mock.comments[0].Body)                      //nolint:typecheck
+	assert.Equal(ts.T(), withMarker("report"), ts.mock.comments[1].Body)                      //nolint:typecheck
+	assert.Equal(ts.T(), "reporter", ts.mock.comments[1].User.Login)                          //nolint:typecheck
+	assert.NotContains(ts.T(), ts.mock.requests, "PATCH /repos/owner/repo/issues/comments/1") //nolint:typecheck
+}
+
+// GITHUB_TOKEN of GitHub Actions can't request the authenticated user, the comments of the Actions bot are updated then
+func (ts *GitHubPublisherTestSuite) TestGitHubActionsTokenComment() {
+	ts.mock.login = ""
+	ts.mock.comments = append(ts.mock.comments, &gitHubComment{Id: 1, Body: withMarker("first report"), User: gitHubUser{Login: gitHubActionsLogin}})
+
+	publisher, err := NewGitHubPublisher("owner/repo", 7, "secret")
+	assert.Nil(ts.T(), err) //nolint:typecheck
+
+	assert.ErrorContains(ts.T(), publisher.Publish("second report"), "returned status 403") //nolint:typecheck
+
+	ts.T().Setenv("GITHUB_ACTIONS", "true") //nolint:typecheck
+
+	assert.Nil(ts.T(), publisher.Publish("second report"))                      //nolint:typecheck
+	assert.Len(ts.T(), ts.mock.comments, 1)                                     //nolint:typecheck
+	assert.Equal(ts.T(), withMarker("second report"), ts.mock.comments[0].Body) //nolint:typecheck
+}
+
+func (ts *GitHubPublisherTestSuite) TestApiErrorReturned() {
+	publisher, err := NewGitHubPublisher("owner/repo", 7, "wrong")
+	assert.Nil(ts.T(), err) //nolint:typecheck
+
+	assert.ErrorContains(ts.T(), publisher.Publish("report"), "returned status 401") //nolint:typecheck
+}
+
+func (ts *GitHubPublisherTestSuite) TestParametersFromEnvironment() {
+	eventFile := path.Join(ts.T().TempDir(), "event.json") //nolint:typecheck
+	if err := os.WriteFile(eventFile, []byte(`{"pull_request": {"number": 42}}`), 0644); err != nil {
+		assert.FailNow(ts.T(), "Could not create file for test: %s", eventFile) //nolint:typecheck
+	}
+
+	ts.T().Setenv("GITHUB_REPOSITORY", "owner/other") //nolint:typecheck
+	ts.T().Setenv("GITHUB_TOKEN", "token")            //nolint:typecheck
+	ts.T().Setenv("GITHUB_EVENT_PATH", eventFile)     //nolint:typecheck
+
+	publisher, err := NewGitHubPublisher("", 0, "")
+
+	assert.Nil(ts.T(), err)                                   //nolint:typecheck
+	assert.Equal(ts.T(), "owner/other", publisher.Repository) //nolint:typecheck
+	assert.Equal(ts.T(), 42, publisher.PullRequest)           //nolint:typecheck
+	assert.Equal(ts.T(), "token", publisher.Token)            //nolint:typecheck
+}
+
+func (ts *GitHubPublisherTestSuite) TestMissingParameters() {
+	ts.T().Setenv("GITHUB_REPOSITORY", "") //nolint:typecheck
+	ts.T().Setenv("GITHUB_TOKEN", "")      //nolint:typecheck
+	ts.T().Setenv("GITHUB_EVENT_PATH", "") //nolint:typecheck
+	ts.T().Setenv("GITHUB_REF", "")        //nolint:typecheck
+
+	_, err := NewGitHubPublisher("", 0, "")
+
+	assert.ErrorContains(ts.T(), err, "GitHub repository")          //nolint:typecheck
+	assert.ErrorContains(ts.T(), err, "GitHub token")               //nolint:typecheck
+	assert.ErrorContains(ts.T(), err, "GitHub pull request number") //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestGitHubPublisher(t *testing.T) {
+	suite.Run(t, new(GitHubPublisherTestSuite))
+}
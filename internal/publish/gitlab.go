@@ -0,0 +1,151 @@
+package publish
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	gitLabDefaultApiUrl = "https://gitlab.com/api/v4"
+	gitLabPageSize      = 100
+)
+
+// GitLabPublisher creates or updates the single (sticky) note of the report in the merge request
+type GitLabPublisher struct {
+	ApiUrl       string
+	Project      string //Project ID or its path with namespace, e.g. `group/project`
+	MergeRequest int    //Internal ID (IID) of the merge request
+	Token        string
+
+	client *apiClient
+}
+
+type gitLabNote struct {
+	Id     int64      `json:"id"`
+	Body   string     `json:"body"`
+	System bool       `json:"system"`
+	Author gitLabUser `json:"author"`
+}
+
+type gitLabUser struct {
+	Id int64 `json:"id"`
+}
+
+// NewGitLabPublisher function creates the publisher, taking not specified parameters from GitLab CI environment
+// variables: CI_API_V4_URL, CI_PROJECT_ID, CI_MERGE_REQUEST_IID and GITLAB_TOKEN
+func NewGitLabPublisher(project string, mergeRequest int, token string) (*GitLabPublisher, error) {
+	p := &GitLabPublisher{
+		ApiUrl:       strings.TrimSuffix(os.Getenv("CI_API_V4_URL"), "/"),
+		Project:      project,
+		MergeRequest: mergeRequest,
+		Token:        token,
+	}
+
+	if len(p.ApiUrl) == 0 {
+		p.ApiUrl = gitLabDefaultApiUrl
+	}
+
+	if len(p.Project) == 0 {
+		p.Project = os.Getenv("CI_PROJECT_ID")
+	}
+
+	if len(p.Token) == 0 {
+		p.Token = os.Getenv("GITLAB_TOKEN")
+	}
+
+	if p.MergeRequest == 0 {
+		p.MergeRequest, _ = strconv.Atoi(os.Getenv("CI_MERGE_REQUEST_IID"))
+	}
+
+	if err := errors.Join(
+		checkIfSpecified(p.Project, "GitLab project (--gitlab-project or CI_PROJECT_ID)"),
+		checkIfSpecified(p.Token, "GitLab token (--gitlab-token or GITLAB_TOKEN)"),
+		func() error {
+			if p.MergeRequest <= 0 {
+				return errors.New("GitLab merge request IID (--gitlab-merge-request or CI_MERGE_REQUEST_IID) must be specified")
+			}
+
+			return nil
+		}(),
+	); err != nil {
+		return nil, err
+	}
+
+	p.client = newApiClient(map[string]string{
+		"PRIVATE-TOKEN": p.Token,
+	})
+
+	return p, nil
+}
+
+func (p *GitLabPublisher) Name() string {
+	return "gitlab"
+}
+
+// Publish function updates the note posted earlier (found by the hidden marker), or creates the new one
+func (p *GitLabPublisher) Publish(body string) error {
+	publishContext := log.WithFields(log.Fields{
+		"project":       p.Project,
+		"merge_request": p.MergeRequest,
+	})
+
+	existing, err := p.findNote()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]string{"body": withMarker(body)}
+
+	if existing != nil {
+		publishContext.WithField("note_id", existing.Id).Info("Updating of the existing merge request note")
+
+		_, err = p.client.do(http.MethodPut, fmt.Sprintf("%s/%d", p.notesUrl(), existing.Id), payload, nil)
+
+		return err
+	}
+
+	publishContext.Info("Creating of the new merge request note")
+
+	_, err = p.client.do(http.MethodPost, p.notesUrl(), payload, nil)
+
+	return err
+}
+
+func (p *GitLabPublisher) notesUrl() string {
+	return fmt.Sprintf("%s/projects/%s/merge_requests/%d/notes", p.ApiUrl, url.PathEscape(p.Project), p.MergeRequest)
+}
+
+// findNote function returns the note with the marker posted by the authenticated user. Notes of other users are
+// skipped even if they contain the marker, so nobody else could get their note updated with the report
+func (p *GitLabPublisher) findNote() (*gitLabNote, error) {
+	var user gitLabUser
+	if _, err := p.client.do(http.MethodGet, p.ApiUrl+"/user", nil, &user); err != nil {
+		return nil, fmt.Errorf("could not get the authenticated GitLab user: %w", err)
+	}
+
+	for page := "1"; len(page) > 0; {
+		var notes []*gitLabNote
+
+		headers, err := p.client.do(http.MethodGet, fmt.Sprintf("%s?per_page=%d&page=%s", p.notesUrl(), gitLabPageSize, page), nil, &notes)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, note := range notes {
+			if !note.System && hasMarker(note.Body) && note.Author.Id == user.Id {
+				return note, nil
+			}
+		}
+
+		page = headers.Get("X-Next-Page")
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,135 @@
+package publish
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// gitLabMock is the local stand-in of GitLab REST API, which keeps notes of the single merge request
+type gitLabMock struct {
+	userId   int64 //ID of the authenticated user
+	notes    []*gitLabNote
+	requests []string
+}
+
+func (m *gitLabMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.requests = append(m.requests, r.Method+" "+r.URL.RawPath)
+
+	if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	notesPath := "/api/v4/projects/group%2Fproject/merge_requests/3/notes"
+
+	var payload gitLabNote
+	_ = json.NewDecoder(r.Body).Decode(&payload)
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v4/user":
+		_ = json.NewEncoder(w).Encode(gitLabUser{Id: m.userId})
+	case r.Method == http.MethodGet && r.URL.RawPath == notesPath:
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		from := min((page-1)*gitLabPageSize, len(m.notes))
+		to := min(page*gitLabPageSize, len(m.notes))
+		if to < len(m.notes) {
+			w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+		}
+		_ = json.NewEncoder(w).Encode(m.notes[from:to])
+	case r.Method == http.MethodPost && r.URL.RawPath == notesPath:
+		payload.Id = int64(len(m.notes) + 1)
+		payload.Author.Id = m.userId
+		m.notes = append(m.notes, &payload)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(payload)
+	case r.Method == http.MethodPut:
+		for _, note := range m.notes {
+			if r.URL.RawPath == fmt.Sprintf("%s/%d", notesPath, note.Id) {
+				note.Body = payload.Body
+				_ = json.NewEncoder(w).Encode(note)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+type GitLabPublisherTestSuite struct {
+	suite.Suite
+
+	mock   *gitLabMock
+	server *httptest.Server
+}
+
+func (ts *GitLabPublisherTestSuite) SetupTest() {
+	ts.mock = &gitLabMock{userId: 5}
+	ts.server = httptest.NewServer(ts.mock)
+
+	ts.T().Setenv("CI_API_V4_URL", ts.server.URL+"/api/v4") //nolint:typecheck
+	ts.T().Setenv("CI_PROJECT_ID", "group/project")         //nolint:typecheck
+	ts.T().Setenv("CI_MERGE_REQUEST_IID", "3")              //nolint:typecheck
+	ts.T().Setenv("GITLAB_TOKEN", "secret")                 //nolint:typecheck
+}
+
+func (ts *GitLabPublisherTestSuite) TearDownTest() {
+	ts.server.Close()
+}
+
+func (ts *GitLabPublisherTestSuite) TestNoteCreatedThenUpdated() {
+	for i := 0; i < gitLabPageSize+5; i++ {
+		ts.mock.notes = append(ts.mock.notes, &gitLabNote{Id: int64(i + 1), Body: "approved"})
+	}
+	// System notes are never updated, even if they contain the marker
+	ts.mock.notes = append(ts.mock.notes, &gitLabNote{Id: int64(gitLabPageSize + 6), Body: withMarker("system"), System: true, Author: gitLabUser{Id: 5}})
+
+	publisher, err := NewGitLabPublisher("", 0, "")
+	assert.Nil(ts.T(), err) //nolint:typecheck
+
+	assert.Nil(ts.T(), publisher.Publish("first report"))                                  //nolint:typecheck
+	assert.Len(ts.T(), ts.mock.notes, gitLabPageSize+7)                                    //nolint:typecheck
+	assert.Equal(ts.T(), withMarker("first report"), ts.mock.notes[gitLabPageSize+6].Body) //nolint:typecheck
+
+	assert.Nil(ts.T(), publisher.Publish("second report"))                                  //nolint:typecheck
+	assert.Len(ts.T(), ts.mock.notes, gitLabPageSize+7)                                     //nolint:typecheck
+	assert.Equal(ts.T(), withMarker("second report"), ts.mock.notes[gitLabPageSize+6].Body) //nolint:typecheck
+	assert.Equal(ts.T(), withMarker("system"), ts.mock.notes[gitLabPageSize+5].Body)        //nolint:typecheck
+}
+
+// Notes of other users are never updated, even if they contain the marker
+func (ts *GitLabPublisherTestSuite) TestForeignNoteSkipped() {
+	ts.mock.notes = append(ts.mock.notes, &gitLabNote{Id: 1, Body: withMarker("forged"), Author: gitLabUser{Id: 8}})
+
+	publisher, err := NewGitLabPublisher("", 0, "")
+	assert.Nil(ts.T(), err) //nolint:typecheck
+
+	assert.Nil(ts.T(), publisher.Publish("report"))                   //nolint:typecheck
+	assert.Len(ts.T(), ts.mock.notes, 2)                              //nolint:typecheck
+	assert.Equal(ts.T(), withMarker("forged"), ts.mock.notes[0].Body) //nolint:typecheck
+	assert.Equal(ts.T(), withMarker("report"), ts.mock.notes[1].Body) //nolint:typecheck
+	assert.Equal(ts.T(), int64(5), ts.mock.notes[1].Author.Id)        //nolint:typecheck
+}
+
+func (ts *GitLabPublisherTestSuite) TestMissingParameters() {
+	ts.T().Setenv("CI_PROJECT_ID", "")        //nolint:typecheck
+	ts.T().Setenv("CI_MERGE_REQUEST_IID", "") //nolint:typecheck
+
+	_, err := NewGitLabPublisher("", 0, "")
+
+	assert.ErrorContains(ts.T(), err, "GitLab project")           //nolint:typecheck
+	assert.ErrorContains(ts.T(), err, "GitLab merge request IID") //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestGitLabPublisher(t *testing.T) {
+	suite.Run(t, new(GitLabPublisherTestSuite))
+}
@@ -0,0 +1,140 @@
+package publish
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Hidden HTML marker which allows to find the comment posted by the App earlier
+	commentMarker = "<!-- tf-plan-reporter -->"
+
+	requestTimeout = 30 * time.Second
+	connectTimeout = 10 * time.Second
+
+	errorResponseSizeLimit = 64 * 1024 // Only the beginning of error response is put to the error message
+)
+
+// Publisher posts the rendered report somewhere, e.g. as a pull request comment
+type Publisher interface {
+	Name() string
+	Publish(body string) error
+}
+
+// MarkerSize function returns the amount of characters which are added by publishers to the report body
+func MarkerSize() int {
+	return len(commentMarker) + 1
+}
+
+func withMarker(body string) string {
+	return commentMarker + "\n" + body
+}
+
+func hasMarker(body string) bool {
+	return strings.Contains(body, commentMarker)
+}
+
+// apiClient is the thin JSON REST API client shared by publishers
+type apiClient struct {
+	httpClient *http.Client
+	headers    map[string]string
+}
+
+func newApiClient(headers map[string]string) *apiClient {
+	return &apiClient{
+		httpClient: &http.Client{Timeout: requestTimeout},
+		headers:    headers,
+	}
+}
+
+// newStreamingApiClient function returns the client for big responses, which are streamed, e.g. plan downloads.
+// Only connecting and waiting for the response headers are limited in time, reading of the body is not, since it
+// depends on the size of the response
+func newStreamingApiClient(headers map[string]string, headerTimeout time.Duration) *apiClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	transport.ResponseHeaderTimeout = headerTimeout
+
+	return &apiClient{
+		httpClient: &http.Client{Transport: transport},
+		headers:    headers,
+	}
+}
+
+// do function sends the request with JSON encoded `payload` (if not nil) and decodes JSON response to `result` (if not nil).
+// It returns response headers, since some APIs use them for pagination
+func (c *apiClient) do(method string, url string, payload interface{}, result interface{}) (http.Header, error) {
+	response, err := c.send(method, url, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if result != nil && len(responseBody) > 0 {
+		if err := json.Unmarshal(responseBody, result); err != nil {
+			return nil, fmt.Errorf("could not decode response of %s %s: %w", method, url, err)
+		}
+	}
+
+	return response.Header, nil
+}
+
+// send function sends the request with JSON encoded `payload` (if not nil) and returns the response with successful
+// status, whose body is not read yet, so it might be streamed. The body must be closed by the caller
+func (c *apiClient) send(method string, url string, payload interface{}) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	request, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range c.headers {
+		request.Header.Set(key, value)
+	}
+
+	requestContext := log.WithFields(log.Fields{
+		"method": method,
+		"url":    url,
+	})
+	requestContext.Debug("Sending of API request")
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	requestContext.WithField("status", response.StatusCode).Debug("API response has been received")
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		defer response.Body.Close()
+
+		responseBody, _ := io.ReadAll(io.LimitReader(response.Body, errorResponseSizeLimit))
+
+		return nil, fmt.Errorf("%s %s returned status %d: %s", method, url, response.StatusCode, strings.TrimSpace(string(responseBody)))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,147 @@
+package publish
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	tfJson "github.com/hashicorp/terraform-json"
+
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+const (
+	TfcTaskPassed = "passed"
+	TfcTaskFailed = "failed"
+
+	DefaultMaxPlanSize = 1024 * 1024 * 1024
+)
+
+// TfcRunTaskClient is the client of Terraform Cloud / Enterprise API used by run task: it downloads the plan JSON
+// and sends the task result back to the callback url. Both use the access token from the run task request
+// https://developer.hashicorp.com/terraform/enterprise/api-docs/run-tasks/run-tasks-integration
+type TfcRunTaskClient struct {
+	client      *apiClient
+	downloader  *apiClient //Streams the plan JSON without limiting the time of the whole download
+	maxPlanSize int64      //Max size of the downloaded plan JSON in bytes
+}
+
+// TfcTaskResult is the result of run task evaluation shown in the run of Terraform Cloud
+type TfcTaskResult struct {
+	Status   string //passed or failed
+	Message  string //Short summary of the result
+	Outcomes []*TfcTaskOutcome
+}
+
+// TfcTaskOutcome is the detailed finding of run task, e.g. a critical resource removal
+type TfcTaskOutcome struct {
+	Id          string
+	Description string
+	Body        string //Markdown
+	Tags        map[string][]TfcTag
+}
+
+type TfcTag struct {
+	Label string `json:"label"`
+	Level string `json:"level,omitempty"` //none, info, warning or error
+}
+
+func NewTfcRunTaskClient(accessToken string, maxPlanSize int64) *TfcRunTaskClient {
+	if maxPlanSize <= 0 {
+		maxPlanSize = DefaultMaxPlanSize
+	}
+
+	headers := map[string]string{
+		"Authorization": "Bearer " + accessToken,
+		"Content-Type":  "application/vnd.api+json",
+	}
+
+	return &TfcRunTaskClient{
+		maxPlanSize: maxPlanSize,
+		client:      newApiClient(headers),
+		downloader:  newStreamingApiClient(headers, requestTimeout),
+	}
+}
+
+// DownloadPlan function downloads the plan JSON of the run. Terraform Cloud redirects the request to the storage,
+// the access token is not forwarded there, because the http client drops the Authorization header on redirect
+// to another host. The plan is decoded while it's streamed, and the download fails if the plan exceeds the max size.
+// The download of big plan might take long, so only waiting for the response is limited in time
+func (c *TfcRunTaskClient) DownloadPlan(url string) (*tfJson.Plan, error) {
+	response, err := c.downloader.send(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	plan, err := processing.DecodePlan(&sizeLimitedReader{reader: response.Body, limit: c.maxPlanSize})
+	if err != nil {
+		return nil, fmt.Errorf("could not decode plan JSON of %s: %w", url, err)
+	}
+
+	return plan, nil
+}
+
+// sizeLimitedReader fails, once more than `limit` bytes are read. Unlike io.LimitReader, which just cuts the stream
+// at the limit, it makes the cause of the failure clear
+type sizeLimitedReader struct {
+	reader io.Reader
+	limit  int64
+	read   int64
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	if remaining := r.limit - r.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := r.reader.Read(p)
+	if r.read += int64(n); r.read > r.limit {
+		return n, fmt.Errorf("plan JSON exceeds the limit of %d bytes", r.limit)
+	}
+
+	return n, err
+}
+
+// SendResult function patches the task result callback with the status, message and outcomes of the evaluation
+func (c *TfcRunTaskClient) SendResult(callbackUrl string, result *TfcTaskResult) error {
+	type outcome struct {
+		Type       string `json:"type"`
+		Attributes struct {
+			OutcomeId   string              `json:"outcome-id"`
+			Description string              `json:"description"`
+			Body        string              `json:"body,omitempty"`
+			Tags        map[string][]TfcTag `json:"tags,omitempty"`
+		} `json:"attributes"`
+	}
+
+	outcomes := make([]*outcome, 0, len(result.Outcomes))
+	for _, item := range result.Outcomes {
+		o := &outcome{Type: "task-result-outcomes"}
+		o.Attributes.OutcomeId = item.Id
+		o.Attributes.Description = item.Description
+		o.Attributes.Body = item.Body
+		o.Attributes.Tags = item.Tags
+
+		outcomes = append(outcomes, o)
+	}
+
+	payload := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type": "task-results",
+			"attributes": map[string]interface{}{
+				"status":  result.Status,
+				"message": result.Message,
+			},
+			"relationships": map[string]interface{}{
+				"outcomes": map[string]interface{}{
+					"data": outcomes,
+				},
+			},
+		},
+	}
+
+	_, err := c.client.do(http.MethodPatch, callbackUrl, payload, nil)
+
+	return err
+}
@@ -0,0 +1,37 @@
+package publish
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The download of plan is limited by the time of waiting for the response only, not by the time of reading the body
+func TestTfcDownloadPlanStreamed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stuck" {
+			time.Sleep(300 * time.Millisecond)
+		}
+
+		_, _ = io.WriteString(w, `{"format_version": "1.2", "resource_changes": [`)
+		w.(http.Flusher).Flush()
+
+		time.Sleep(300 * time.Millisecond) //Longer than the timeout of the response headers
+		_, _ = io.WriteString(w, `{"address": "null_resource.a", "type": "null_resource", "name": "a", "change": {"actions": ["delete"]}}]}`)
+	}))
+	defer server.Close()
+
+	client := NewTfcRunTaskClient("token", 0)
+	client.downloader = newStreamingApiClient(client.client.headers, 100*time.Millisecond)
+
+	plan, err := client.DownloadPlan(server.URL + "/plan")
+	assert.Nil(t, err)                                                  //nolint:typecheck
+	assert.Equal(t, "null_resource.a", plan.ResourceChanges[0].Address) //nolint:typecheck
+
+	_, err = client.DownloadPlan(server.URL + "/stuck")
+	assert.ErrorContains(t, err, "timeout awaiting response headers") //nolint:typecheck
+}
@@ -0,0 +1,42 @@
+package publish
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+)
+
+// WebhookPublisher posts the notification payload (e.g. Slack Block Kit message or Teams Adaptive Card) to incoming webhook
+type WebhookPublisher struct {
+	Type string
+	Url  string
+
+	client *apiClient
+}
+
+// NewWebhookPublisher function returns the publisher for incoming webhook. Environment variables in the url, like
+// ${SLACK_WEBHOOK_URL}, are expanded, so that the secret url doesn't have to be kept in the config file
+func NewWebhookPublisher(webhookType string, url string) (*WebhookPublisher, error) {
+	url = os.ExpandEnv(url)
+	if len(url) == 0 {
+		return nil, errors.New("webhook url is not specified or expanded to empty string")
+	}
+
+	return &WebhookPublisher{
+		Type:   webhookType,
+		Url:    url,
+		client: newApiClient(nil),
+	}, nil
+}
+
+func (p *WebhookPublisher) Name() string {
+	return p.Type
+}
+
+// Publish function posts the payload as is, since it's already JSON document
+func (p *WebhookPublisher) Publish(body string) error {
+	_, err := p.client.do(http.MethodPost, p.Url, json.RawMessage(body), nil)
+
+	return err
+}
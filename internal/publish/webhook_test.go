@@ -0,0 +1,37 @@
+package publish
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookPublisher(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_WEBHOOK_URL", server.URL)
+
+	publisher, err := NewWebhookPublisher("slack", "${TEST_WEBHOOK_URL}")
+	assert.Nil(t, err)                         //nolint:typecheck
+	assert.Equal(t, server.URL, publisher.Url) //nolint:typecheck
+
+	assert.Nil(t, publisher.Publish(`{"text":"Plan: 1 to add"}`)) //nolint:typecheck
+	assert.JSONEq(t, `{"text":"Plan: 1 to add"}`, received)       //nolint:typecheck
+
+	_, err = NewWebhookPublisher("teams", "${TEST_UNSET_WEBHOOK_URL}")
+	assert.NotNil(t, err) //nolint:typecheck
+}
@@ -2,51 +2,199 @@ package report
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path"
+	"strings"
 
+	"github.com/arshvin/tf-plan-reporter/internal/config"
 	"github.com/arshvin/tf-plan-reporter/internal/processing"
 	log "github.com/sirupsen/logrus"
 )
 
-// PrintReport function prepares and print the report from the data collected by function RunSearch
-func PrintReport(reportData *processing.ConsolidatedJson, outputFilename string) {
+const (
+	stdoutFileName = "-"
+)
+
+// PrintReport function prepares and print the report from the data collected by function RunSearch and evaluated by
+// the decision maker
+func PrintReport(reportData *processing.ConsolidatedJson, settings *config.AppConfig) error {
 
 	totalAmount := reportData.TotalItems()
 	log.WithField("total_amount", totalAmount).Debug("Report table contains elements")
 
-	if totalAmount > 0 {
-		var reports []*report
-
-		if len(outputFilename) > 0 {
-			output, err := os.Create(outputFilename)
+	summary := NewSummary(reportData, settings.StartedAt)
+	defer log.Info(summary.Line())
 
+	if totalAmount > 0 {
+		for _, output := range reportOutputs(settings) {
+			parts, err := renderOutput(reportData, settings, summary, output)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 
-			log.WithField("file_name", outputFilename).Debug("The empty report file has been created")
+			if err := writeParts(output, parts); err != nil {
+				return err
+			}
+		}
+
+	} else {
+		fmt.Print("THERE IS NO ANY REPORT DATA")
+	}
+
+	if settings.GitHubActions || gitHubActionsEnabled() {
+		log.Debug("Writing of GitHub Actions job summary, step outputs and annotations")
 
-			defer output.Close()
+		sink := newGitHubActionsSink(settings.TemplatesDir)
+		if err := sink.Write(reportData, summary, reportPath(settings)); err != nil {
+			return err
+		}
+	}
 
-			reports = []*report{
-				forGitHub(output),
-				forStdout(),
-			}
+	return nil
+}
 
-		} else {
-			reports = []*report{
-				forStdout(),
+// reportPath function returns the file name of the main report, the markdown one is preferred
+func reportPath(settings *config.AppConfig) string {
+	var result string
+
+	for _, output := range reportOutputs(settings) {
+		if len(output.FileName) == 0 || output.FileName == stdoutFileName {
+			continue
+		}
+
+		if strings.HasSuffix(output.Format, "markdown") {
+			return output.FileName
+		}
+
+		if len(result) == 0 {
+			result = output.FileName
+		}
+	}
+
+	return result
+}
+
+// RenderBody function renders the report of the format as a single piece of text, e.g. to publish it as a comment.
+// The report is fitted into the size limit of the format, reduced by `reserved` amount of characters
+func RenderBody(reportData *processing.ConsolidatedJson, settings *config.AppConfig, format string, reserved int) (string, error) {
+	r, err := newReport(format, nil, settings.TemplatesDir)
+	if err != nil {
+		return "", err
+	}
+
+	if r.sizeLimit > 0 {
+		r.sizeLimit -= reserved
+	}
+	r.summary = NewSummary(reportData, settings.StartedAt)
+	r.Prepare(reportData)
+
+	parts, err := r.Render()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(parts, ""), nil
+}
+
+// RenderSummary function renders the summary of the report of the format only, e.g. to print the verdict of the gate
+func RenderSummary(reportData *processing.ConsolidatedJson, settings *config.AppConfig, format string) (*Summary, string, error) {
+	r, err := newReport(format, nil, settings.TemplatesDir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	r.summary = NewSummary(reportData, settings.StartedAt)
+	content, err := r.renderSummary()
+
+	return r.summary, content, err
+}
+
+// RenderOutput function renders the report of the output format as a single piece of text, fitted into `max_size` of
+// the output if it's set, or into the size limit of the format otherwise
+func RenderOutput(reportData *processing.ConsolidatedJson, settings *config.AppConfig, output config.ReportOutput) (string, error) {
+	output.Split = false
+
+	parts, err := renderOutput(reportData, settings, NewSummary(reportData, settings.StartedAt), output)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(parts, ""), nil
+}
+
+func renderOutput(reportData *processing.ConsolidatedJson, settings *config.AppConfig, summary *Summary, output config.ReportOutput) ([]string, error) {
+	r, err := newReport(output.Format, nil, settings.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if output.MaxSize > 0 {
+		r.sizeLimit = output.MaxSize
+	}
+	r.split = output.Split
+	r.summary = summary
+
+	r.Prepare(reportData)
+
+	return r.Render()
+}
+
+// reportOutputs function returns all requested outputs of report. The report is always printed to Stdout, and
+// additionally to the files specified either by dedicated cli args, or in `outputs` section of config file
+func reportOutputs(settings *config.AppConfig) []config.ReportOutput {
+	var outputs []config.ReportOutput
+
+	if len(settings.ReportFileName) > 0 {
+		outputs = append(outputs, config.ReportOutput{Format: "github_markdown", FileName: settings.ReportFileName})
+	}
+
+	if len(settings.HtmlReportFileName) > 0 {
+		outputs = append(outputs, config.ReportOutput{Format: "html", FileName: settings.HtmlReportFileName})
+	}
+
+	outputs = append(outputs, settings.Outputs...)
+
+	if settings.SplitReports {
+		for i := range outputs {
+			outputs[i].Split = true
+		}
+	}
+
+	return append(outputs, config.ReportOutput{Format: "stdout", FileName: stdoutFileName})
+}
+
+// writeParts function writes rendered report to the output file. If the report has been split, every part is written
+// to the numbered file next to the output one, e.g. `report.md` -> `report.part1.md`, `report.part2.md`
+func writeParts(output config.ReportOutput, parts []string) error {
+	if len(output.FileName) == 0 || output.FileName == stdoutFileName {
+		log.WithField("format", output.Format).Debug("The report is going to be printed to Stdout")
+
+		for _, part := range parts {
+			if _, err := io.WriteString(os.Stdout, part); err != nil {
+				return err
 			}
+		}
+
+		return nil
+	}
 
-			log.Debug("The report is going to be printed to Stdout")
+	for i, part := range parts {
+		fileName := output.FileName
+		if len(parts) > 1 {
+			ext := path.Ext(fileName)
+			fileName = fmt.Sprintf("%s.part%d%s", strings.TrimSuffix(fileName, ext), i+1, ext)
 		}
 
-		for _, r := range reports {
-			r.Prepare(reportData)
-			r.Print()
+		if err := os.WriteFile(fileName, []byte(part), 0644); err != nil {
+			return err
 		}
 
-	} else {
-		fmt.Print("THERE IS NO ANY REPORT DATA")
+		log.WithFields(log.Fields{
+			"file_name": fileName,
+			"format":    output.Format,
+		}).Debug("The report file has been written")
 	}
+
+	return nil
 }
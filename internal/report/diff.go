@@ -0,0 +1,190 @@
+package report
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+// ModifiedChange is the planned change of the resource, which is present in both runs, but differs
+type ModifiedChange struct {
+	Old     *JsonResource
+	New     *JsonResource
+	Details []string //Human readable differences, e.g. `action: update -> delete`
+}
+
+// PlanDiff is the difference of planned changes between two runs. Resources without changes (no-op), suppressed updates
+// of ignored attributes and data sources are not taken into account
+type PlanDiff struct {
+	OldSummary *Summary
+	NewSummary *Summary
+	Added      []*JsonResource //Planned changes which are new since the old run
+	Removed    []*JsonResource //Planned changes which are not planned anymore
+	Modified   []*ModifiedChange
+	Same       int //Amount of planned changes, which are the same in both runs
+}
+
+// HasChanges function checks if there are any differences between the runs
+func (d *PlanDiff) HasChanges() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Modified) > 0
+}
+
+// VerdictChanged function checks if the gate verdict is different between the runs
+func (d *PlanDiff) VerdictChanged() bool {
+	return d.OldSummary.Verdict != d.NewSummary.Verdict
+}
+
+// Description function returns the short description of the planned change, e.g. `delete (blocked)`
+func (r *JsonResource) Description() string {
+	if len(r.Removal) > 0 {
+		return fmt.Sprintf("%s (%s)", r.Action, r.Removal)
+	}
+
+	return r.Action
+}
+
+// NewPlanDiff function compares planned changes of the old and new reports
+func NewPlanDiff(oldReport *JsonReport, newReport *JsonReport) *PlanDiff {
+	diff := &PlanDiff{
+		OldSummary: oldReport.Summary,
+		NewSummary: newReport.Summary,
+	}
+
+	oldChanges := plannedChanges(oldReport)
+	newChanges := plannedChanges(newReport)
+
+	for key, newResource := range newChanges {
+		oldResource, found := oldChanges[key]
+		if !found {
+			diff.Added = append(diff.Added, newResource)
+			continue
+		}
+
+		if details := resourceDifferences(oldResource, newResource); len(details) > 0 {
+			diff.Modified = append(diff.Modified, &ModifiedChange{Old: oldResource, New: newResource, Details: details})
+		} else {
+			diff.Same++
+		}
+	}
+
+	for key, oldResource := range oldChanges {
+		if _, found := newChanges[key]; !found {
+			diff.Removed = append(diff.Removed, oldResource)
+		}
+	}
+
+	compare := func(a, b *JsonResource) int {
+		return cmp.Or(cmp.Compare(a.Module, b.Module), cmp.Compare(a.Address, b.Address))
+	}
+	slices.SortFunc(diff.Added, compare)
+	slices.SortFunc(diff.Removed, compare)
+	slices.SortFunc(diff.Modified, func(a, b *ModifiedChange) int {
+		return compare(a.New, b.New)
+	})
+
+	return diff
+}
+
+// plannedChanges function returns the resources to be changed, keyed by module and address
+func plannedChanges(document *JsonReport) map[string]*JsonResource {
+	result := make(map[string]*JsonResource)
+
+	for _, resource := range document.Resources {
+		if resource.Action == "no-op" || resource.Action == "read" || resource.Noise {
+			continue
+		}
+
+		result[resource.Module+"\x00"+resource.Address] = resource
+	}
+
+	return result
+}
+
+func resourceDifferences(oldResource *JsonResource, newResource *JsonResource) []string {
+	var details []string
+
+	if oldResource.Action != newResource.Action {
+		details = append(details, fmt.Sprintf("action: %s -> %s", oldResource.Action, newResource.Action))
+	}
+
+	if oldResource.Removal != newResource.Removal && len(oldResource.Removal) > 0 && len(newResource.Removal) > 0 {
+		details = append(details, fmt.Sprintf("removal: %s -> %s", oldResource.Removal, newResource.Removal))
+	}
+
+	oldAttributes := attributesByPath(oldResource.Changes)
+	newAttributes := attributesByPath(newResource.Changes)
+
+	var paths []string
+	for attrPath := range newAttributes {
+		paths = append(paths, attrPath)
+	}
+	for attrPath := range oldAttributes {
+		if _, found := newAttributes[attrPath]; !found {
+			paths = append(paths, attrPath)
+		}
+	}
+	slices.Sort(paths)
+
+	for _, attrPath := range paths {
+		oldChange, oldFound := oldAttributes[attrPath]
+		newChange, newFound := newAttributes[attrPath]
+
+		switch {
+		case !oldFound:
+			details = append(details, fmt.Sprintf("attribute %s is changed now", attrPath))
+		case !newFound:
+			details = append(details, fmt.Sprintf("attribute %s is not changed anymore", attrPath))
+		case oldChange.After != newChange.After:
+			details = append(details, fmt.Sprintf("attribute %s: %s -> %s", attrPath, valueOrNull(oldChange.After), valueOrNull(newChange.After)))
+		}
+	}
+
+	return details
+}
+
+func attributesByPath(changes []*processing.AttributeChange) map[string]*processing.AttributeChange {
+	result := make(map[string]*processing.AttributeChange)
+	for _, change := range changes {
+		result[change.Path] = change
+	}
+
+	return result
+}
+
+func valueOrNull(value string) string {
+	if len(value) == 0 {
+		return "null"
+	}
+
+	return value
+}
+
+// RenderDiff function renders the difference with help of `diff_<format>.tmpl` template, where format is either
+// `markdown` or `stdout`
+func RenderDiff(diff *PlanDiff, format string, templatesDir string) (string, error) {
+	templates := templatesFS(templatesDir)
+	templateName := fmt.Sprintf("diff_%s.tmpl", format)
+	templatePathName := path.Join(templatesRoot, templateName)
+
+	if _, err := fs.Stat(templates, templatePathName); err != nil {
+		return "", fmt.Errorf("unknown diff format '%s': %w", format, err)
+	}
+
+	diffTmpl, err := template.New(templateName).Funcs(templateFuncs()).ParseFS(templates, templatePathName)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+	if err := diffTmpl.Execute(&output, diff); err != nil {
+		return "", err
+	}
+
+	return output.String(), nil
+}
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+func TestPlanDiff(t *testing.T) {
+	oldReport := &JsonReport{
+		Summary: &Summary{Verdict: VerdictWarnings},
+		Resources: []*JsonResource{
+			{Module: "app", Address: "a.same", Action: "create"},
+			{Module: "app", Address: "a.gone", Action: "update"},
+			{Module: "app", Address: "a.action", Action: "update"},
+			{Module: "app", Address: "a.attrs", Action: "update", Changes: []*processing.AttributeChange{
+				{Path: "size", Before: "1", After: "2"},
+				{Path: "name", Before: `"x"`, After: `"y"`},
+			}},
+			{Module: "app", Address: "a.noop", Action: "no-op"},
+		},
+	}
+	newReport := &JsonReport{
+		Summary: &Summary{Verdict: VerdictBlocked},
+		Resources: []*JsonResource{
+			{Module: "app", Address: "a.same", Action: "create"},
+			{Module: "app", Address: "a.action", Action: "delete", Removal: RemovalBlocked},
+			{Module: "app", Address: "a.attrs", Action: "update", Changes: []*processing.AttributeChange{
+				{Path: "size", Before: "1", After: "3"},
+				{Path: "tags", Before: "", After: `{}`},
+			}},
+			{Module: "db", Address: "a.same", Action: "delete", Removal: RemovalAllowed},
+			{Module: "app", Address: "a.gone", Action: "no-op"},
+		},
+	}
+
+	diff := NewPlanDiff(oldReport, newReport)
+
+	assert.True(t, diff.VerdictChanged()) //nolint:typecheck
+	assert.Equal(t, 1, diff.Same)         //nolint:typecheck
+
+	assert.Len(t, diff.Added, 1)                                     //nolint:typecheck
+	assert.Equal(t, "db", diff.Added[0].Module)                      //nolint:typecheck
+	assert.Equal(t, "delete (allowed)", diff.Added[0].Description()) //nolint:typecheck
+
+	assert.Len(t, diff.Removed, 1)                     //nolint:typecheck
+	assert.Equal(t, "a.gone", diff.Removed[0].Address) //nolint:typecheck
+
+	assert.Len(t, diff.Modified, 2)                                                 //nolint:typecheck
+	assert.Equal(t, []string{"action: update -> delete"}, diff.Modified[0].Details) //nolint:typecheck
+	assert.Equal(t, []string{
+		"attribute name is not changed anymore",
+		"attribute size: 2 -> 3",
+		"attribute tags is changed now",
+	}, diff.Modified[1].Details) //nolint:typecheck
+
+	content, err := RenderDiff(diff, "markdown", "")
+	assert.Nil(t, err)                                                                        //nolint:typecheck
+	assert.Contains(t, content, "**Verdict:** WARNINGS → BLOCKED")                            //nolint:typecheck
+	assert.Contains(t, content, "| `db` | `a.same` | delete (allowed) |")                     //nolint:typecheck
+	assert.Contains(t, content, "attribute size: 2 -&gt; 3<br>attribute tags is changed now") //nolint:typecheck
+
+	content, err = RenderDiff(diff, "stdout", "")
+	assert.Nil(t, err)                                    //nolint:typecheck
+	assert.Contains(t, content, "  - app: a.gone update") //nolint:typecheck
+
+	_, err = RenderDiff(diff, "html", "")
+	assert.NotNil(t, err) //nolint:typecheck
+}
@@ -3,8 +3,11 @@ package report
 import (
 	"cmp"
 	"embed"
+	"errors"
 	"fmt"
+	htmlTemplate "html/template"
 	"io"
+	"io/fs"
 	"os"
 	"path"
 	"slices"
@@ -13,6 +16,7 @@ import (
 
 	"github.com/alexeyco/simpletable"
 
+	"github.com/arshvin/tf-plan-reporter/internal/config"
 	"github.com/arshvin/tf-plan-reporter/internal/processing"
 	log "github.com/sirupsen/logrus"
 )
@@ -27,20 +31,95 @@ const (
 	created
 	updated
 	unchanged
+	read
 )
 
+// reportItem is the data of a single resource row of report. Besides own fields, all fields of processing.ResourceData
+// are accessible in templates: .Address, .Module, .Type, .Name, .Index, .Actions, .Changes (list of items with .Path,
+// .Before, .After fields) and .ActionLabel. Use .FilterLabel to tell noise updates from real ones
+type reportItem struct {
+	*processing.ResourceData
+	Answer   string                 //Answer on question "Allowed to remove", filled for deleted resources only
+	Allowed  bool                   //Whether the resource is allowed to be removed, makes sense for deleted resources only
+	Accepted *config.AcceptedChange //Baseline entry accepting the planned change, if any
+	Approval *config.Approval       //Approval releasing the removal, if any
+}
+
+// FilterLabel function returns the planned action of the resource, e.g. `update`, or `noise` if only ignored attributes
+// are updated, since such updates are reported as unchanged resources
+func (item *reportItem) FilterLabel() string {
+	if item.Noise {
+		return "noise"
+	}
+
+	return item.ActionLabel()
+}
+
+// ApprovalNote function returns the note on the approval of the removal, e.g. `approved by alice`, or empty string
+// if the removal is not approved
+func (item *reportItem) ApprovalNote() string {
+	if item.Approval == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("approved by %s", item.Approval.Approver)
+}
+
+// reportData is the data of an action type section of report. It's passed to the template of the format for every
+// not empty section, unless the format is rendered as a whole document
 type reportData struct {
-	ItemCount    int
-	TableContent *simpletable.Table
-	ActionType   byte
+	ItemCount    int                //Amount of resources in the section
+	TableContent *simpletable.Table //Pre-rendered table of resources in the table style of the format
+	ActionType   byte               //Action type of the section, use .ActionName in templates to get human readable one
+	Items        []*reportItem      //Resources of the section, sorted by resource type
+	OmittedCount int                //Amount of resources omitted from the section due to size limit of the format
+
+	offset int //Index of the first resource of the section, if it's a chunk of the bigger one
+}
+
+// ActionName function returns the name of action type of the report section
+func (rd *reportData) ActionName() string {
+	switch rd.ActionType {
+	case deleted:
+		return "deleted"
+	case created:
+		return "created"
+	case updated:
+		return "updated"
+	case read:
+		return "read"
+	default:
+		return "unchanged"
+	}
+}
+
+type moduleData struct {
+	Name  string        //Name of terragrunt/terraform module
+	Items []*reportItem //Resources of the module, every resource is listed once regardless of amount of its actions
+}
+
+// documentData is the data of reports, which are rendered in a single pass over the whole template, instead
+// of the template overlaying for every action type section
+type documentData struct {
+	Summary  *Summary      //Overall summary of planned changes
+	Sections []*reportData //Not empty action type sections in order: deleted, created, updated, unchanged, read
+	Modules  []*moduleData //All parsed modules sorted by name, including ones without changes
 }
 
 type report struct {
-	template   string
-	output     io.Writer
-	data       []*reportData
-	answers    map[bool]string
-	tableStyle *simpletable.Style
+	template      string
+	templates     fs.FS
+	output        io.Writer
+	data          []*reportData
+	modules       []string
+	summary       *Summary
+	answers       map[bool]string
+	tableStyle    *simpletable.Style
+	wholeDocument bool
+	html          bool                          //Whether the whole document is HTML one, rendered by html/template escaping all values by their context
+	sizeLimit     int                           //Max size of rendered report in characters, 0 means unlimited
+	split         bool                          //Whether the report exceeding the size limit should be split to few parts instead of truncating
+	renderer      func(*report) (string, error) //Renders the report without templates, e.g. to JSON payload
 }
 
 func forGitHub(output io.Writer) *report {
@@ -52,6 +131,52 @@ func forGitHub(output io.Writer) *report {
 			false: ":x:",                // https://emojipedia.org/cross-mark#technical
 		},
 		tableStyle: simpletable.StyleMarkdown,
+		sizeLimit:  gitHubCommentSizeLimit,
+	}
+}
+
+// forGitLab function returns the report for GitLab merge request notes. GitLab renders markdown inside of
+// `<details>` block only if it's separated by empty lines
+func forGitLab(output io.Writer) *report {
+	return &report{
+		output:   output,
+		template: "templates/gitlab_markdown.tmpl",
+		answers: map[bool]string{
+			true:  ":white_check_mark:",
+			false: ":x:",
+		},
+		tableStyle: simpletable.StyleMarkdown,
+		sizeLimit:  gitLabNoteSizeLimit,
+	}
+}
+
+// forAzureDevOps function returns the report for Azure DevOps pull request comments, which don't support emoji
+// shortcodes, therefore unicode symbols are used instead
+func forAzureDevOps(output io.Writer) *report {
+	return &report{
+		output:   output,
+		template: "templates/azure_devops_markdown.tmpl",
+		answers: map[bool]string{
+			true:  "✅",
+			false: "❌",
+		},
+		tableStyle: simpletable.StyleMarkdown,
+		sizeLimit:  azureDevOpsCommentLimit,
+	}
+}
+
+// forBitbucket function returns the report for Bitbucket pull request comments. Bitbucket does not render HTML,
+// so there are no collapsible sections, just headings
+func forBitbucket(output io.Writer) *report {
+	return &report{
+		output:   output,
+		template: "templates/bitbucket_markdown.tmpl",
+		answers: map[bool]string{
+			true:  "✅",
+			false: "❌",
+		},
+		tableStyle: simpletable.StyleMarkdown,
+		sizeLimit:  bitbucketCommentSizeLimit,
 	}
 }
 
@@ -67,9 +192,43 @@ func forStdout() *report {
 	}
 }
 
+func forHtml(output io.Writer) *report {
+	return &report{
+		output:   output,
+		template: "templates/html.tmpl",
+		answers: map[bool]string{
+			true:  "yes",
+			false: "no",
+		},
+		wholeDocument: true,
+		html:          true,
+	}
+}
+
+// renderHtml function renders the whole HTML document. Unlike markdown formats, values are escaped by html/template
+// according to the context they are put into, so neither resource addresses nor approvers nor attribute values
+// might inject the markup
+func (r *report) renderHtml() ([]string, error) {
+	log.WithField("output_template", path.Base(r.template)).Debug("Output of whole HTML document")
+
+	document, err := htmlTemplate.New(path.Base(r.template)).Funcs(htmlTemplate.FuncMap(templateFuncs())).ParseFS(r.templates, r.template)
+	if err != nil {
+		return nil, err
+	}
+
+	var output strings.Builder
+	if err := document.Execute(&output, r.document()); err != nil {
+		return nil, err
+	}
+
+	return []string{output.String()}, nil
+}
+
+// Prepare function sorts the evaluated data into the tables of the report
 func (r *report) Prepare(data *processing.ConsolidatedJson) {
 
-	queue := []byte{deleted, created, updated, unchanged}
+	r.modules = data.Modules
+	queue := []byte{deleted, created, updated, unchanged, read}
 
 	var answers map[bool]string
 
@@ -85,6 +244,8 @@ func (r *report) Prepare(data *processing.ConsolidatedJson) {
 			value = data.Updated
 		case unchanged:
 			value = data.Unchanged
+		case read:
+			value = data.Read
 		}
 
 		amount := len(value)
@@ -105,9 +266,13 @@ func (r *report) Prepare(data *processing.ConsolidatedJson) {
 			}
 
 			item := &reportData{
-				TableContent: formatMainContent(r.tableStyle, value, answers, tableLogger),
-				ItemCount:    amount,
-				ActionType:   actionType,
+				Items:      prepareItems(value, answers, tableLogger),
+				ItemCount:  amount,
+				ActionType: actionType,
+			}
+
+			if r.tableStyle != nil {
+				item.TableContent = formatMainContent(r.tableStyle, item.Items, answers != nil, tableLogger)
 			}
 
 			r.data = append(r.data, item)
@@ -115,41 +280,121 @@ func (r *report) Prepare(data *processing.ConsolidatedJson) {
 	}
 }
 
-func (r *report) Print() {
+// Print function renders the report and writes it to the output. If the report was split to few parts,
+// they are written one by one
+func (r *report) Print() error {
+	parts, err := r.Render()
+	if err != nil {
+		return err
+	}
 
-	funcMap := template.FuncMap{
-		"upper": strings.ToUpper,
+	for _, part := range parts {
+		if _, err := io.WriteString(r.output, part); err != nil {
+			return err
+		}
 	}
 
-	parentTemplate := template.Must(template.New(path.Base(r.template)).Funcs(funcMap).ParseFS(content, r.template))
+	return nil
+}
 
-	for _, item := range r.data {
+// Render function renders the report taking into account the size limit of the format. It returns more than one
+// part only if the report exceeds the limit and splitting was requested
+func (r *report) Render() ([]string, error) {
+	if r.renderer != nil {
+		if r.summary == nil {
+			return nil, fmt.Errorf("the summary is required to render the report")
+		}
 
-		log.WithFields(
-			log.Fields{
-				"action_type":     item.ActionType,
-				"output_template": path.Base(r.template),
-			}).Debug("Output of result table")
+		output, err := r.renderer(r)
+		if err != nil {
+			return nil, err
+		}
 
-		var templatePathName string
+		return []string{output}, nil
+	}
 
-		switch item.ActionType {
-		case deleted:
-			templatePathName = r.getTemplate("deleted.tmpl")
-		case created:
-			templatePathName = r.getTemplate("created.tmpl")
-		case updated:
-			templatePathName = r.getTemplate("updated.tmpl")
-		case unchanged:
-			templatePathName = r.getTemplate("unchanged.tmpl")
-		}
+	if r.templates == nil {
+		r.templates = content
+	}
+
+	if r.html {
+		return r.renderHtml()
+	}
+
+	parentTemplate, err := template.New(path.Base(r.template)).Funcs(templateFuncs()).ParseFS(r.templates, r.template)
+	if err != nil {
+		return nil, err
+	}
 
-		resultTemplate := template.Must(template.Must(parentTemplate.Clone()).ParseFS(content, templatePathName))
+	if r.wholeDocument {
+		log.WithField("output_template", path.Base(r.template)).Debug("Output of whole document")
 
-		if err := resultTemplate.Execute(r.output, item); err != nil {
-			log.Fatal(err)
+		var output strings.Builder
+		if err := parentTemplate.Execute(&output, r.document()); err != nil {
+			return nil, err
 		}
+
+		return []string{output.String()}, nil
+	}
+
+	renderer := &sectionRenderer{report: r, parent: parentTemplate, rendered: make(map[sectionKey]string)}
+
+	if renderer.header, err = r.renderSummary(); err != nil {
+		return nil, err
+	}
+
+	result, err := renderer.renderAll(r.data)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.sizeLimit == 0 || textSize(result) <= r.sizeLimit {
+		return []string{result}, nil
+	}
+
+	logger := log.WithFields(log.Fields{
+		"output_template": path.Base(r.template),
+		"size_limit":      r.sizeLimit,
+		"size":            textSize(result),
+	})
+
+	if r.split {
+		logger.Warn("The report exceeds the size limit, it's going to be split to few parts")
+
+		return renderer.split()
 	}
+
+	logger.Warn("The report exceeds the size limit, low priority sections are going to be collapsed and long tables truncated")
+
+	return renderer.fit()
+}
+
+// renderSummary function renders the summary header of the report with help of `<format>/summary.tmpl` template,
+// if the format has it
+func (r *report) renderSummary() (string, error) {
+	templatePathName := r.getTemplate("summary.tmpl")
+
+	if r.summary == nil {
+		return "", nil
+	}
+
+	if _, err := fs.Stat(r.templates, templatePathName); errors.Is(err, fs.ErrNotExist) {
+		log.WithField("template", templatePathName).Debug("The format has no summary template")
+
+		return "", nil
+	}
+
+	summaryTemplate, err := template.New(path.Base(templatePathName)).Funcs(templateFuncs()).ParseFS(r.templates, templatePathName)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+	if err := summaryTemplate.Execute(&output, r.summary); err != nil {
+		return "", err
+	}
+
+	return output.String(), nil
 }
 
 func (r *report) getTemplate(name string) string {
@@ -158,10 +403,80 @@ func (r *report) getTemplate(name string) string {
 
 }
 
-func formatMainContent(tableStyle *simpletable.Style, items []*processing.ResourceData, deleteTableAnswers map[bool]string, logger *log.Entry) *simpletable.Table {
+// document function groups prepared items by modules, every resource is listed only once even if it's presented
+// in few action type sections (e.g. replaced resources)
+func (r *report) document() *documentData {
+	doc := &documentData{Summary: r.summary, Sections: r.data}
+
+	modules := make(map[string]*moduleData)
+	seen := make(map[*processing.ResourceData]bool)
+
+	for _, name := range r.modules {
+		modules[name] = &moduleData{Name: name}
+		doc.Modules = append(doc.Modules, modules[name])
+	}
+
+	for _, section := range r.data {
+		for _, item := range section.Items {
+			if seen[item.ResourceData] {
+				continue
+			}
+			seen[item.ResourceData] = true
+
+			module, ok := modules[item.Module]
+			if !ok {
+				module = &moduleData{Name: item.Module}
+				modules[item.Module] = module
+				doc.Modules = append(doc.Modules, module)
+			}
+
+			module.Items = append(module.Items, item)
+		}
+	}
+
+	slices.SortFunc(doc.Modules, func(a, b *moduleData) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return doc
+}
+
+func prepareItems(resources []*processing.ResourceData, deleteTableAnswers map[bool]string, logger *log.Entry) []*reportItem {
+	logger.Debug("Sorting elements data elements before table report filling")
+	slices.SortFunc(resources, func(a, b *processing.ResourceData) int {
+		return cmp.Compare(a.Type, b.Type)
+	})
+
+	var items []*reportItem
+	for _, resource := range resources {
+		item := &reportItem{ResourceData: resource, Accepted: resource.AcceptedBy()}
+
+		if deleteTableAnswers != nil {
+			item.Approval = resource.ApprovedBy()
+
+			if item.Accepted != nil {
+				item.Allowed = true
+				item.Answer = RemovalAccepted //The same answer in every format
+			} else if item.Approval != nil {
+				item.Allowed = true
+				item.Answer = item.ApprovalNote()
+			} else {
+				item.Allowed = resource.IsAllowed()
+				item.Answer = deleteTableAnswers[item.Allowed]
+			}
+			logger.WithField("resource_type", resource.Type).Debugf("Is it OK to remove: %s", item.Answer)
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func formatMainContent(tableStyle *simpletable.Style, items []*reportItem, withAnswers bool, logger *log.Entry) *simpletable.Table {
 	headers := []string{"Type", "Name", "Index (if any)"}
 
-	if deleteTableAnswers != nil {
+	if withAnswers {
 		headers = slices.Insert(headers, 0, "Allowed to remove")
 	}
 
@@ -179,27 +494,24 @@ func formatMainContent(tableStyle *simpletable.Style, items []*processing.Resour
 		)
 	}
 
-	logger.Debug("Sorting elements data elements before table report filling")
-	slices.SortFunc(items, func(a, b *processing.ResourceData) int {
-		return cmp.Compare(a.Type, b.Type)
-	})
-
-	decisionMaker := processing.GetDecisionMaker()
-
 	logger.Debug("Filling of report table rows")
 	for _, item := range items {
+		name := item.Name
+		if item.Noise {
+			name += " (noise)"
+		} else if item.Accepted != nil && !withAnswers {
+			name += " (accepted)"
+		}
+
 		row := []*simpletable.Cell{
 			{Align: simpletable.AlignLeft, Text: item.Type},
-			{Align: simpletable.AlignLeft, Text: item.Name},
+			{Align: simpletable.AlignLeft, Text: name},
 			{Align: simpletable.AlignLeft, Text: item.Index},
 		}
 
-		if deleteTableAnswers != nil {
-			answer := deleteTableAnswers[decisionMaker.IsAllowedForRemoval(item.Type)]
-			logger.WithField("resource_type", item.Type).Debugf("Is it OK to remove: %s", answer)
-
+		if withAnswers {
 			row = slices.Insert(row, 0,
-				&simpletable.Cell{Align: simpletable.AlignCenter, Text: answer})
+				&simpletable.Cell{Align: simpletable.AlignCenter, Text: item.Answer})
 		}
 
 		table.Body.Cells = append(table.Body.Cells, row)
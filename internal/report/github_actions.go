@@ -0,0 +1,175 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	gitHubStepSummarySizeLimit = 1024 * 1024 // https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#step-isolation-and-limits
+)
+
+// gitHubActionsSink writes the report to the GitHub Actions job summary, key values to the step outputs and
+// workflow commands (annotations) for every removal
+type gitHubActionsSink struct {
+	stepSummaryFile string
+	outputFile      string
+	templatesDir    string
+	commands        io.Writer
+}
+
+// gitHubActionsEnabled function checks if the App is running inside of GitHub Actions workflow
+func gitHubActionsEnabled() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+func newGitHubActionsSink(templatesDir string) *gitHubActionsSink {
+	return &gitHubActionsSink{
+		stepSummaryFile: os.Getenv("GITHUB_STEP_SUMMARY"),
+		outputFile:      os.Getenv("GITHUB_OUTPUT"),
+		templatesDir:    templatesDir,
+		commands:        os.Stdout,
+	}
+}
+
+func (s *gitHubActionsSink) Write(data *processing.ConsolidatedJson, summary *Summary, reportPath string) error {
+	if err := s.writeStepSummary(data, summary); err != nil {
+		return err
+	}
+
+	if err := s.writeOutputs(summary, reportPath); err != nil {
+		return err
+	}
+
+	return s.writeAnnotations(data)
+}
+
+// writeStepSummary function appends the report in GitHub markdown format to the job summary file
+func (s *gitHubActionsSink) writeStepSummary(data *processing.ConsolidatedJson, summary *Summary) error {
+	if len(s.stepSummaryFile) == 0 {
+		log.Debug("GITHUB_STEP_SUMMARY is not set, the job summary is skipped")
+
+		return nil
+	}
+
+	r, err := newReport("github_markdown", nil, s.templatesDir)
+	if err != nil {
+		return err
+	}
+
+	r.sizeLimit = gitHubStepSummarySizeLimit
+	r.summary = summary
+	r.Prepare(data)
+
+	parts, err := r.Render()
+	if err != nil {
+		return err
+	}
+
+	return appendToFile(s.stepSummaryFile, parts[0]+"\n")
+}
+
+// writeOutputs function writes key values of the report to the step outputs file
+func (s *gitHubActionsSink) writeOutputs(summary *Summary, reportPath string) error {
+	if len(s.outputFile) == 0 {
+		log.Debug("GITHUB_OUTPUT is not set, the step outputs are skipped")
+
+		return nil
+	}
+
+	if len(reportPath) > 0 {
+		if absPath, err := filepath.Abs(reportPath); err == nil {
+			reportPath = absPath
+		}
+	}
+
+	outputs := []struct {
+		key   string
+		value interface{}
+	}{
+		{"to_add", summary.ToAdd},
+		{"to_change", summary.ToChange},
+		{"to_destroy", summary.ToDestroy},
+		{"to_replace", summary.ToReplace},
+		{"unchanged", summary.Unchanged},
+		{"critical_removals", summary.BlockedRemovals},
+		{"verdict", summary.Verdict},
+		{"summary", summary.Line()},
+		{"report_path", reportPath},
+	}
+
+	var content strings.Builder
+	for _, output := range outputs {
+		fmt.Fprintf(&content, "%s=%v\n", output.key, output.value)
+	}
+
+	return appendToFile(s.outputFile, content.String())
+}
+
+// writeAnnotations function emits `::error` workflow command for every removal of critical resource
+// and `::warning` one for every allowed or approved removal and expired baseline entry.
+// Removals accepted by baseline are skipped
+func (s *gitHubActionsSink) writeAnnotations(data *processing.ConsolidatedJson) error {
+	for _, item := range data.Deleted {
+		if item.AcceptedBy() != nil {
+			continue
+		}
+
+		approval := item.ApprovedBy()
+
+		command, title := "warning", "Resource removal"
+		if approval != nil {
+			title = "Approved resource removal"
+		} else if !item.IsAllowed() {
+			command, title = "error", "Critical resource removal"
+		}
+
+		message := fmt.Sprintf("%s is going to be deleted in module %s", item.Address, item.Module)
+		if item.Actions.Replace() {
+			message = fmt.Sprintf("%s is going to be replaced in module %s", item.Address, item.Module)
+		}
+		if approval != nil {
+			message += fmt.Sprintf(", approved by %s: %s", approval.Approver, approval.Reason)
+		}
+
+		if _, err := fmt.Fprintf(s.commands, "::%s title=%s::%s\n", command, escapeProperty(title), escapeData(message)); err != nil {
+			return err
+		}
+	}
+
+	for _, expired := range data.ExpiredAcceptances {
+		if _, err := fmt.Fprintf(s.commands, "::warning title=%s::%s\n", escapeProperty("Expired baseline entry"), escapeData(expired)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func appendToFile(fileName string, content string) error {
+	file, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(content)
+
+	return err
+}
+
+// escapeData function escapes the message of workflow command, the same way as @actions/core does
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty function escapes the property value of workflow command, the same way as @actions/core does
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+type GitHubActionsTestSuite struct {
+	suite.Suite
+
+	tmpDir string
+	data   *processing.ConsolidatedJson
+	dm     *processing.DecisionMaker
+}
+
+func (ts *GitHubActionsTestSuite) SetupTest() {
+	ts.tmpDir = ts.T().TempDir() //nolint:typecheck
+
+	settings := &config.AppConfig{DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{"critical": true}}}
+	ts.dm = processing.NewDecisionMaker(settings)
+
+	ts.data = new(processing.ConsolidatedJson)
+	ts.data.Parse("mod", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		{Address: "critical.a", Type: "critical", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionDelete}}},
+		{Address: "regular.b", Type: "regular", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionDelete}}},
+	}}, ts.dm)
+	ts.dm.Evaluate(ts.data)
+}
+
+func (ts *GitHubActionsTestSuite) TestSinkWritesEverything() {
+	var commands bytes.Buffer
+
+	sink := &gitHubActionsSink{
+		stepSummaryFile: path.Join(ts.tmpDir, "summary.md"),
+		outputFile:      path.Join(ts.tmpDir, "output"),
+		commands:        &commands,
+	}
+
+	if err := os.WriteFile(sink.outputFile, []byte("previous=1\n"), 0644); err != nil {
+		assert.FailNow(ts.T(), "Could not create file for test: %s", sink.outputFile) //nolint:typecheck
+	}
+
+	err := sink.Write(ts.data, NewSummary(ts.data, time.Time{}), "/tmp/report.md")
+	assert.Nil(ts.T(), err) //nolint:typecheck
+
+	stepSummary, _ := os.ReadFile(sink.stepSummaryFile)
+	assert.Contains(ts.T(), string(stepSummary), "FOLLOWING RESOURCES WILL BE DELETED: 2") //nolint:typecheck
+
+	outputs, _ := os.ReadFile(sink.outputFile)
+	assert.Contains(ts.T(), string(outputs), "previous=1\n")                 //nolint:typecheck
+	assert.Contains(ts.T(), string(outputs), "critical_removals=1\n")        //nolint:typecheck
+	assert.Contains(ts.T(), string(outputs), "verdict=blocked\n")            //nolint:typecheck
+	assert.Contains(ts.T(), string(outputs), "report_path=/tmp/report.md\n") //nolint:typecheck
+
+	assert.Equal(ts.T(), "::error title=Critical resource removal::critical.a is going to be deleted in module mod\n"+ //nolint:typecheck
+		"::warning title=Resource removal::regular.b is going to be deleted in module mod\n", commands.String())
+}
+
+func (ts *GitHubActionsTestSuite) TestEscaping() {
+	assert.Equal(ts.T(), "a%25b%0Ac", escapeData("a%b\nc"))    //nolint:typecheck
+	assert.Equal(ts.T(), "a%3Ab%2Cc", escapeProperty("a:b,c")) //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestGitHubActions(t *testing.T) {
+	suite.Run(t, new(GitHubActionsTestSuite))
+}
@@ -0,0 +1,129 @@
+package report
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+// markupData function returns the plans with markup in resource addresses, module names, approvers and attribute values
+func markupData() *processing.ConsolidatedJson {
+	dm := processing.NewDecisionMaker(&config.AppConfig{
+		DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{"critical": true}},
+		Approvals:   []*config.Approval{{Address: "critical.d", Approver: "<img src=x onerror=alert(3)>"}},
+	})
+
+	data := new(processing.ConsolidatedJson)
+	data.Parse("app", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		{Address: `critical.a["<script>alert(1)</script>"]`, Type: "critical", Name: "a", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionDelete}}},
+		{Address: "other.b", Type: "other", Name: "b", Change: &tfJson.Change{
+			Actions: tfJson.Actions{tfJson.ActionUpdate},
+			Before:  map[string]interface{}{"name": "b", "<i>tag</i>": "a"},
+			After:   map[string]interface{}{"name": `"><script>alert(2)</script>`, "<i>tag</i>": "b"},
+		}},
+		{Address: "critical.d", Type: "critical", Name: "d", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionDelete}}},
+	}}, dm)
+	data.Parse("web/<main>", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		{Address: "other.c", Type: "other", Name: "c", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionCreate}}},
+	}}, dm)
+	dm.Evaluate(data)
+
+	return data
+}
+
+func renderHtml(t *testing.T, data *processing.ConsolidatedJson, templatesDir string) string {
+	r, err := newReport("html", nil, templatesDir)
+	assert.Nil(t, err) //nolint:typecheck
+
+	r.summary = NewSummary(data, time.Now())
+	r.Prepare(data)
+
+	parts, err := r.Render()
+	assert.Nil(t, err)      //nolint:typecheck
+	assert.Len(t, parts, 1) //nolint:typecheck
+
+	return parts[0]
+}
+
+func TestHtmlReport(t *testing.T) {
+	output := renderHtml(t, markupData(), "")
+
+	// The only script of the document is the filtering one
+	assert.Equal(t, 1, strings.Count(output, "<script>"))                                                                                       //nolint:typecheck
+	assert.NotContains(t, output, "<img")                                                                                                       //nolint:typecheck
+	assert.Contains(t, output, `<td class="address">critical.a[&#34;&lt;script&gt;alert(1)&lt;/script&gt;&#34;]</td>`)                          //nolint:typecheck
+	assert.Contains(t, output, `data-address="critical.a[&#34;&lt;script&gt;alert(1)&lt;/script&gt;&#34;]"`)                                    //nolint:typecheck
+	assert.Contains(t, output, `<td>name</td><td>&#34;b&#34;</td><td>&#34;\&#34;\u003e\u003cscript\u003ealert(2)\u003c/script\u003e&#34;</td>`) //nolint:typecheck
+	assert.Contains(t, output, "<tr><td>&lt;i&gt;tag&lt;/i&gt;</td>")                                                                           //nolint:typecheck
+	assert.Contains(t, output, "<td>approved by &lt;img src=x onerror=alert(3)&gt;</td>")                                                       //nolint:typecheck
+
+	// Resources are grouped by modules
+	app := strings.Index(output, "<summary>app (3)</summary>")
+	web := strings.Index(output, "<summary>web/&lt;main&gt; (1)</summary>")
+	assert.Greater(t, app, -1)                                              //nolint:typecheck
+	assert.Greater(t, web, app)                                             //nolint:typecheck
+	assert.Less(t, strings.Index(output, `data-address="other.b"`), web)    //nolint:typecheck
+	assert.Greater(t, strings.Index(output, `data-address="other.c"`), web) //nolint:typecheck
+
+	// Rows carry the attributes used by the filters
+	assert.Contains(t, output, `<tr class="resource blocked" data-action="delete" data-address="critical.a`) //nolint:typecheck
+	assert.Contains(t, output, `<tr class="resource" data-action="delete" data-address="critical.d">`)       //nolint:typecheck
+	assert.Contains(t, output, `<tr class="resource" data-action="update" data-address="other.b">`)          //nolint:typecheck
+	assert.Contains(t, output, `<tr class="resource" data-action="create" data-address="other.c">`)          //nolint:typecheck
+	for _, action := range []string{"delete", "replace", "create", "update", "noise", "no-op", "read"} {
+		assert.Contains(t, output, `<input type="checkbox" class="filter" value="`+action+`"`) //nolint:typecheck
+	}
+}
+
+// Values are escaped by default, even if the user supplied template doesn't care of it
+func TestHtmlReportUserTemplateEscaped(t *testing.T) {
+	templatesDir := t.TempDir()
+	userTemplate := `{{ range .Modules }}{{ range .Items }}<p title="{{ .Address }}">{{ .Answer }}</p>{{ end }}{{ end }}`
+	if err := os.WriteFile(path.Join(templatesDir, "html.tmpl"), []byte(userTemplate), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	output := renderHtml(t, markupData(), templatesDir)
+
+	assert.NotContains(t, output, "<script")                                                              //nolint:typecheck
+	assert.NotContains(t, output, "<img")                                                                 //nolint:typecheck
+	assert.Contains(t, output, `<p title="critical.a[&#34;&lt;script&gt;alert(1)&lt;/script&gt;&#34;]">`) //nolint:typecheck
+}
+
+// Updates of ignored attributes only are filtered separately, not as real updates
+func TestHtmlReportNoise(t *testing.T) {
+	dm := processing.NewDecisionMaker(&config.AppConfig{
+		DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{}},
+		ConfigFile:  config.ConfigFile{IgnoreRules: []config.IgnoreRule{{ResourceType: "other", Attributes: []string{"tags"}}}},
+	})
+
+	data := new(processing.ConsolidatedJson)
+	data.Parse("app", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		{Address: "other.noisy", Type: "other", Name: "noisy", Change: &tfJson.Change{
+			Actions: tfJson.Actions{tfJson.ActionUpdate},
+			Before:  map[string]interface{}{"tags": "a"},
+			After:   map[string]interface{}{"tags": "b"},
+		}},
+		{Address: "other.real", Type: "other", Name: "real", Change: &tfJson.Change{
+			Actions: tfJson.Actions{tfJson.ActionUpdate},
+			Before:  map[string]interface{}{"size": 1},
+			After:   map[string]interface{}{"size": 2},
+		}},
+	}}, dm)
+	dm.Evaluate(data)
+
+	output := renderHtml(t, data, "")
+
+	assert.Contains(t, output, `<tr class="resource" data-action="noise" data-address="other.noisy">`) //nolint:typecheck
+	assert.Contains(t, output, `<td class="action noise">update (noise)</td>`)                         //nolint:typecheck
+	assert.Contains(t, output, `<tr class="resource" data-action="update" data-address="other.real">`) //nolint:typecheck
+	assert.Contains(t, output, `<input type="checkbox" class="filter" value="noise">`)                 //nolint:typecheck
+}
@@ -0,0 +1,135 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+const (
+	RemovalBlocked  = "blocked"
+	RemovalAllowed  = "allowed"
+	RemovalAccepted = "accepted"
+	RemovalApproved = "approved"
+)
+
+// JsonReport is the machine readable report written by `json` format
+type JsonReport struct {
+	Summary   *Summary        `json:"summary"`
+	Resources []*JsonResource `json:"resources"`
+}
+
+// JsonResource is the planned change of the resource. Replaced resources are listed once
+type JsonResource struct {
+	Address string                        `json:"address"`
+	Module  string                        `json:"module"`
+	Type    string                        `json:"type"`
+	Name    string                        `json:"name"`
+	Index   string                        `json:"index,omitempty"`
+	Action  string                        `json:"action"`            //create, update, delete, replace, read or no-op
+	Removal string                        `json:"removal,omitempty"` //Decision over deleted and replaced resources: blocked, allowed, accepted or approved
+	Changes []*processing.AttributeChange `json:"changes,omitempty"`
+	Noise   bool                          `json:"noise,omitempty"` //The update changes ignored attributes only
+
+	Accepted      bool   `json:"accepted,omitempty"`      //Whether the planned change is accepted by baseline
+	Justification string `json:"justification,omitempty"` //Justification of the baseline entry
+
+	Approval *JsonApproval `json:"approval,omitempty"` //Approval of the removal, recorded for auditing
+}
+
+// JsonApproval is the approval releasing the removal of the resource
+type JsonApproval struct {
+	Approver string `json:"approver"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// forJson function returns the report rendered to JSON document
+func forJson(output io.Writer) *report {
+	return &report{
+		output:   output,
+		renderer: renderJson,
+		answers: map[bool]string{
+			true:  RemovalAllowed,
+			false: RemovalBlocked,
+		},
+	}
+}
+
+// NewJsonReport function returns the machine readable report of the collected data, the same as written by `json` format
+func NewJsonReport(data *processing.ConsolidatedJson, startedAt time.Time) *JsonReport {
+	r := forJson(nil)
+	r.summary = NewSummary(data, startedAt)
+	r.Prepare(data)
+
+	return r.jsonReport()
+}
+
+// ReadJsonReport function reads the report written by `json` format
+func ReadJsonReport(fileName string) (*JsonReport, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	document := new(JsonReport)
+	if err := json.Unmarshal(content, document); err != nil {
+		return nil, fmt.Errorf("could not parse JSON report %s: %w", fileName, err)
+	}
+
+	if document.Summary == nil {
+		return nil, fmt.Errorf("%s is not JSON report, it has no summary", fileName)
+	}
+
+	return document, nil
+}
+
+func renderJson(r *report) (string, error) {
+	payload, err := json.MarshalIndent(r.jsonReport(), "", "  ")
+
+	return string(payload), err
+}
+
+func (r *report) jsonReport() *JsonReport {
+	document := &JsonReport{
+		Summary:   r.summary,
+		Resources: []*JsonResource{},
+	}
+
+	seen := make(map[*processing.ResourceData]bool)
+	for _, section := range r.data {
+		for _, item := range section.Items {
+			if seen[item.ResourceData] {
+				continue
+			}
+			seen[item.ResourceData] = true
+
+			resource := &JsonResource{
+				Address: item.Address,
+				Module:  item.Module,
+				Type:    item.Type,
+				Name:    item.Name,
+				Index:   item.Index,
+				Action:  item.ActionLabel(),
+				Removal: item.Answer,
+				Changes: item.Changes,
+				Noise:   item.Noise,
+			}
+			if item.Accepted != nil {
+				resource.Accepted = true
+				resource.Justification = item.Accepted.Justification
+			}
+			if item.Approval != nil {
+				resource.Removal = RemovalApproved
+				resource.Approval = &JsonApproval{Approver: item.Approval.Approver, Reason: item.Approval.Reason}
+			}
+
+			document.Resources = append(document.Resources, resource)
+		}
+	}
+
+	return document
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+func TestJsonReport(t *testing.T) {
+	dm := processing.NewDecisionMaker(&config.AppConfig{DefensePlan: config.DefensePlan{
+		ExceptionalResources: map[string]bool{"critical": true},
+	}})
+
+	data := new(processing.ConsolidatedJson)
+	data.Parse("mod", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		{Address: "critical.a", Type: "critical", Name: "a", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionDelete, tfJson.ActionCreate}}},
+		{Address: "other.b", Type: "other", Name: "b", Index: 1, Change: &tfJson.Change{
+			Actions: tfJson.Actions{tfJson.ActionUpdate},
+			Before:  map[string]interface{}{"size": 1},
+			After:   map[string]interface{}{"size": 2},
+		}},
+	}}, dm)
+	dm.Evaluate(data)
+
+	r, err := newReport("json", nil, "")
+	assert.Nil(t, err) //nolint:typecheck
+
+	r.summary = NewSummary(data, time.Now())
+	r.Prepare(data)
+
+	parts, err := r.Render()
+	assert.Nil(t, err) //nolint:typecheck
+
+	var document JsonReport
+	assert.Nil(t, json.Unmarshal([]byte(parts[0]), &document)) //nolint:typecheck
+
+	assert.Equal(t, VerdictBlocked, document.Summary.Verdict) //nolint:typecheck
+	assert.Equal(t, 1, document.Summary.ToReplace)            //nolint:typecheck
+	assert.Equal(t, []*JsonResource{
+		{Address: "critical.a", Module: "mod", Type: "critical", Name: "a", Action: "replace", Removal: RemovalBlocked},
+		{Address: "other.b", Module: "mod", Type: "other", Name: "b", Index: "1", Action: "update", Changes: []*processing.AttributeChange{
+			{Path: "size", Before: "1", After: "2"},
+		}},
+	}, document.Resources) //nolint:typecheck
+}
+
+func TestJsonReportApprovedRemoval(t *testing.T) {
+	dm := processing.NewDecisionMaker(&config.AppConfig{
+		DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{"critical": true}},
+		Approvals:   []*config.Approval{{Module: "legacy/*", Approver: "alice", Reason: "Decommissioning"}},
+	})
+
+	data := new(processing.ConsolidatedJson)
+	data.Parse("legacy/db", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		{Address: "critical.a", Type: "critical", Name: "a", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionDelete}}},
+	}}, dm)
+	dm.Evaluate(data)
+
+	document := NewJsonReport(data, time.Now())
+
+	assert.Equal(t, VerdictWarnings, document.Summary.Verdict)                                                   //nolint:typecheck
+	assert.Equal(t, 1, document.Summary.ApprovedRemovals)                                                        //nolint:typecheck
+	assert.Equal(t, 0, document.Summary.BlockedRemovals)                                                         //nolint:typecheck
+	assert.Equal(t, RemovalApproved, document.Resources[0].Removal)                                              //nolint:typecheck
+	assert.Equal(t, &JsonApproval{Approver: "alice", Reason: "Decommissioning"}, document.Resources[0].Approval) //nolint:typecheck
+}
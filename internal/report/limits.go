@@ -0,0 +1,276 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/alexeyco/simpletable"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	gitHubCommentSizeLimit    = 65536   // https://github.com/orgs/community/discussions/27190
+	gitLabNoteSizeLimit       = 1000000 // https://docs.gitlab.com/ee/api/notes.html
+	azureDevOpsCommentLimit   = 150000
+	bitbucketCommentSizeLimit = 32768
+)
+
+var (
+	// Sections which are collapsed first, if the report exceeds the size limit
+	lowPrioritySections = []byte{read, unchanged}
+)
+
+// sectionKey identifies the rendered part of a section: rows in range [offset, offset+rows) and the amount of
+// omitted ones. Truncated and chunked copies of the section are created anew every time, so they can't be keyed by pointer
+type sectionKey struct {
+	actionType byte
+	offset     int
+	rows       int
+	omitted    int
+}
+
+// sectionRenderer renders action type sections of the report one by one, caching results, since the same section
+// might be rendered many times during fitting the report to the size limit
+type sectionRenderer struct {
+	report   *report
+	parent   *template.Template
+	header   string //Rendered summary of the report, always placed on top of it
+	rendered map[sectionKey]string
+}
+
+func (sr *sectionRenderer) render(section *reportData) (string, error) {
+	key := sectionKey{actionType: section.ActionType, offset: section.offset, rows: len(section.Items), omitted: section.OmittedCount}
+	if result, ok := sr.rendered[key]; ok {
+		return result, nil
+	}
+
+	log.WithFields(
+		log.Fields{
+			"action_type":     section.ActionType,
+			"output_template": sr.parent.Name(),
+		}).Debug("Output of result table")
+
+	templatePathName := sr.report.getTemplate(section.ActionName() + ".tmpl")
+
+	if _, err := fs.Stat(sr.report.templates, templatePathName); errors.Is(err, fs.ErrNotExist) {
+		log.WithField("template", templatePathName).Warn("The template of section is absent, the section is skipped")
+
+		return "", nil
+	}
+
+	parentClone, err := sr.parent.Clone()
+	if err != nil {
+		return "", err
+	}
+
+	resultTemplate, err := parentClone.ParseFS(sr.report.templates, templatePathName)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+	if err := resultTemplate.Execute(&output, section); err != nil {
+		return "", err
+	}
+
+	sr.rendered[key] = output.String()
+
+	return sr.rendered[key], nil
+}
+
+func (sr *sectionRenderer) renderAll(sections []*reportData) (string, error) {
+	var output strings.Builder
+
+	output.WriteString(sr.header)
+
+	for _, section := range sections {
+		result, err := sr.render(section)
+		if err != nil {
+			return "", err
+		}
+
+		output.WriteString(result)
+	}
+
+	return output.String(), nil
+}
+
+// fit function makes the report smaller than size limit. First of all, low priority sections are collapsed, then tables
+// of all sections, except deleted resources, are truncated to the same max amount of rows. Deleted resources are always kept
+func (sr *sectionRenderer) fit() ([]string, error) {
+	limit := sr.report.sizeLimit
+	sections := slices.Clone(sr.report.data)
+
+	for _, actionType := range lowPrioritySections {
+		if i := slices.IndexFunc(sections, func(s *reportData) bool { return s.ActionType == actionType }); i > -1 {
+			sections[i] = sr.report.truncated(sections[i], 0)
+
+			result, err := sr.renderAll(sections)
+			if err != nil {
+				return nil, err
+			}
+
+			if textSize(result) <= limit {
+				return []string{result}, nil
+			}
+		}
+	}
+
+	maxRows := 0
+	for _, section := range sections {
+		maxRows = max(maxRows, len(section.Items))
+	}
+
+	truncatedTo := func(rows int) []*reportData {
+		result := slices.Clone(sections)
+		for i, section := range result {
+			if section.ActionType != deleted && !slices.Contains(lowPrioritySections, section.ActionType) {
+				result[i] = sr.report.truncated(section, rows)
+			}
+		}
+
+		return result
+	}
+
+	// Binary search of max amount of rows, which still allows to fit the report
+	low, high := 0, maxRows
+	for low < high {
+		middle := (low + high + 1) / 2
+
+		result, err := sr.renderAll(truncatedTo(middle))
+		if err != nil {
+			return nil, err
+		}
+
+		if textSize(result) <= limit {
+			low = middle
+		} else {
+			high = middle - 1
+		}
+	}
+
+	result, err := sr.renderAll(truncatedTo(low))
+	if err != nil {
+		return nil, err
+	}
+
+	if textSize(result) > limit {
+		log.WithFields(log.Fields{
+			"size_limit": limit,
+			"size":       textSize(result),
+		}).Warn("The report still exceeds the size limit, since deleted resources must be kept")
+	}
+
+	return []string{result}, nil
+}
+
+// split function divides the report to few parts, each one is smaller than size limit. Sections which are bigger
+// than the limit themselves, are divided to few chunks by rows with the same caption
+func (sr *sectionRenderer) split() ([]string, error) {
+	limit := sr.report.sizeLimit
+
+	chunks := []string{sr.header}
+	for _, section := range sr.report.data {
+		for offset := 0; offset < len(section.Items); {
+			low, high := 1, len(section.Items)-offset
+			for low < high {
+				middle := (low + high + 1) / 2
+
+				result, err := sr.render(sr.report.chunk(section, offset, middle))
+				if err != nil {
+					return nil, err
+				}
+
+				if textSize(result) <= limit {
+					low = middle
+				} else {
+					high = middle - 1
+				}
+			}
+
+			result, err := sr.render(sr.report.chunk(section, offset, low))
+			if err != nil {
+				return nil, err
+			}
+
+			chunks = append(chunks, result)
+			offset += low
+		}
+	}
+
+	var parts []string
+	var part strings.Builder
+	for _, chunk := range chunks {
+		if part.Len() > 0 && textSize(part.String())+textSize(chunk) > limit {
+			parts = append(parts, part.String())
+			part.Reset()
+		}
+
+		part.WriteString(chunk)
+	}
+
+	if part.Len() > 0 {
+		parts = append(parts, part.String())
+	}
+
+	log.WithField("parts_amount", len(parts)).Debug("The report has been split")
+
+	return parts, nil
+}
+
+// truncated function returns the copy of section with only first `rows` resources in the table
+// and the row with amount of omitted ones
+func (r *report) truncated(section *reportData, rows int) *reportData {
+	if rows >= len(section.Items) {
+		return section
+	}
+
+	result := *section
+	result.Items = section.Items[:rows]
+	result.OmittedCount = len(section.Items) - rows
+
+	if r.tableStyle != nil {
+		result.TableContent = r.table(section, result.Items)
+
+		moreRow := []*simpletable.Cell{{Align: simpletable.AlignLeft, Text: fmt.Sprintf("… and %d more", result.OmittedCount)}}
+		for len(moreRow) < len(result.TableContent.Header.Cells) {
+			moreRow = append(moreRow, &simpletable.Cell{Text: ""})
+		}
+
+		result.TableContent.Body.Cells = append(result.TableContent.Body.Cells, moreRow)
+	}
+
+	return &result
+}
+
+// chunk function returns the copy of section with resources in range [offset, offset+rows)
+func (r *report) chunk(section *reportData, offset int, rows int) *reportData {
+	if offset == 0 && rows >= len(section.Items) {
+		return section
+	}
+
+	result := *section
+	result.Items = section.Items[offset:min(offset+rows, len(section.Items))]
+	result.offset = offset
+
+	if r.tableStyle != nil {
+		result.TableContent = r.table(section, result.Items)
+	}
+
+	return &result
+}
+
+func (r *report) table(section *reportData, items []*reportItem) *simpletable.Table {
+	logger := log.WithField("output_template", r.template)
+
+	return formatMainContent(r.tableStyle, items, section.ActionType == deleted, logger)
+}
+
+func textSize(text string) int {
+	return utf8.RuneCountInString(text)
+}
@@ -0,0 +1,130 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"testing"
+	"text/template"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+type LimitsTestSuite struct {
+	suite.Suite
+
+	data *processing.ConsolidatedJson
+	dm   *processing.DecisionMaker
+}
+
+func (ts *LimitsTestSuite) SetupSuite() {
+	ts.dm = processing.NewDecisionMaker(&config.AppConfig{DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{}}})
+
+	var changes []*tfJson.ResourceChange
+	for actionType, amount := range map[tfJson.Action]int{tfJson.ActionDelete: 20, tfJson.ActionCreate: 200, tfJson.ActionNoop: 500} {
+		for i := 0; i < amount; i++ {
+			changes = append(changes, &tfJson.ResourceChange{
+				Address: fmt.Sprintf("null_resource.%s_%d", actionType, i),
+				Type:    "null_resource",
+				Name:    fmt.Sprintf("%s_%d", actionType, i),
+				Change:  &tfJson.Change{Actions: tfJson.Actions{actionType}},
+			})
+		}
+	}
+
+	ts.data = new(processing.ConsolidatedJson)
+	ts.data.Parse("mod", &tfJson.Plan{ResourceChanges: changes}, ts.dm)
+	ts.dm.Evaluate(ts.data)
+}
+
+func (ts *LimitsTestSuite) prepared(limit int, split bool) *report {
+	r := forGitHub(io.Discard)
+	r.sizeLimit = limit
+	r.split = split
+	r.Prepare(ts.data)
+
+	return r
+}
+
+func (ts *LimitsTestSuite) TestReportWithinLimitIsNotChanged() {
+	parts, err := ts.prepared(0, false).Render()
+
+	assert.Nil(ts.T(), err)                                             //nolint:typecheck
+	assert.Len(ts.T(), parts, 1)                                        //nolint:typecheck
+	assert.Equal(ts.T(), 720, strings.Count(parts[0], "null_resource")) //nolint:typecheck
+}
+
+func (ts *LimitsTestSuite) TestLowPrioritySectionsCollapsedFirst() {
+	full, _ := ts.prepared(0, false).Render()
+	limit := textSize(full[0]) - 10000
+
+	parts, err := ts.prepared(limit, false).Render()
+
+	assert.Nil(ts.T(), err)                                             //nolint:typecheck
+	assert.Len(ts.T(), parts, 1)                                        //nolint:typecheck
+	assert.LessOrEqual(ts.T(), textSize(parts[0]), limit)               //nolint:typecheck
+	assert.Contains(ts.T(), parts[0], "… and 500 more")                 //nolint:typecheck
+	assert.Equal(ts.T(), 220, strings.Count(parts[0], "null_resource")) //nolint:typecheck
+}
+
+func (ts *LimitsTestSuite) TestTablesTruncatedButDeletedKept() {
+	parts, err := ts.prepared(10000, false).Render()
+
+	assert.Nil(ts.T(), err)                               //nolint:typecheck
+	assert.Len(ts.T(), parts, 1)                          //nolint:typecheck
+	assert.LessOrEqual(ts.T(), textSize(parts[0]), 10000) //nolint:typecheck
+	for i := 0; i < 20; i++ {
+		assert.Contains(ts.T(), parts[0], fmt.Sprintf("delete_%d ", i)) //nolint:typecheck
+	}
+	assert.Regexp(ts.T(), `… and \d+ more`, parts[0]) //nolint:typecheck
+}
+
+func (ts *LimitsTestSuite) TestSplitToParts() {
+	parts, err := ts.prepared(10000, true).Render()
+
+	assert.Nil(ts.T(), err)               //nolint:typecheck
+	assert.Greater(ts.T(), len(parts), 1) //nolint:typecheck
+
+	total := 0
+	for _, part := range parts {
+		assert.LessOrEqual(ts.T(), textSize(part), 10000) //nolint:typecheck
+		total += strings.Count(part, "null_resource")
+	}
+	assert.Equal(ts.T(), 720, total) //nolint:typecheck
+}
+
+// Truncated and chunked copies of the same rows are created anew on every step of fitting, but rendered once
+func (ts *LimitsTestSuite) TestRenderedSectionsReused() {
+	r := ts.prepared(0, false)
+	r.templates = content
+	parent, err := template.New(path.Base(r.template)).Funcs(templateFuncs()).ParseFS(content, r.template)
+	assert.Nil(ts.T(), err) //nolint:typecheck
+
+	renderer := &sectionRenderer{report: r, parent: parent, rendered: make(map[sectionKey]string)}
+	section := r.data[len(r.data)-1]
+
+	for i := 0; i < 3; i++ {
+		_, err = renderer.render(r.truncated(section, 10))
+		assert.Nil(ts.T(), err) //nolint:typecheck
+		_, err = renderer.render(r.chunk(section, 10, 10))
+		assert.Nil(ts.T(), err) //nolint:typecheck
+	}
+	assert.Len(ts.T(), renderer.rendered, 2) //nolint:typecheck
+
+	truncated, _ := renderer.render(r.truncated(section, 10))
+	chunk, _ := renderer.render(r.chunk(section, 10, 10))
+	assert.Contains(ts.T(), truncated, "… and") //nolint:typecheck
+	assert.NotContains(ts.T(), chunk, "… and")  //nolint:typecheck
+	assert.NotEqual(ts.T(), truncated, chunk)   //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestLimits(t *testing.T) {
+	suite.Run(t, new(LimitsTestSuite))
+}
@@ -0,0 +1,176 @@
+package report
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+const (
+	matrixTemplate = "matrix_markdown.tmpl"
+)
+
+// MatrixCell is the planned changes of the module in the environment
+type MatrixCell struct {
+	Environment string
+	Present     bool //Whether the module has the plan in the environment
+	ToAdd       int
+	ToChange    int
+	ToDestroy   int
+	ToReplace   int
+	Blocked     int                        //Amount of removals, which are not allowed
+	Divergent   bool                       //The module is going to lose resources here, while it's not in some other environment
+	Items       []*processing.ResourceData //Changed resources, for drill-down
+}
+
+// MatrixRow is the module with its cells, one per environment in the order of `Environments`
+type MatrixRow struct {
+	Module string
+	Cells  []*MatrixCell
+}
+
+// matrixData is passed to `matrix_markdown.tmpl` template
+type matrixData struct {
+	Environments []string
+	Summaries    []*Summary //Per environment, in the order of `Environments`
+	Rows         []*MatrixRow
+}
+
+// HasChanges function checks if the module has any changes in the environment
+func (c *MatrixCell) HasChanges() bool {
+	return c.ToAdd+c.ToChange+c.ToDestroy+c.ToReplace > 0
+}
+
+// Counts function returns the compact representation of the changes: +add ~change -destroy ±replace
+func (c *MatrixCell) Counts() string {
+	if !c.Present {
+		return "n/a"
+	}
+
+	if !c.HasChanges() {
+		return "no changes"
+	}
+
+	var counts []string
+	for _, count := range []struct {
+		sign   string
+		amount int
+	}{{"+", c.ToAdd}, {"~", c.ToChange}, {"-", c.ToDestroy}, {"±", c.ToReplace}} {
+		if count.amount > 0 {
+			counts = append(counts, fmt.Sprintf("%s%d", count.sign, count.amount))
+		}
+	}
+
+	return strings.Join(counts, " ")
+}
+
+// RenderMatrix function renders the matrix report of the same modules across the environments, whose data have been
+// collected separately. `environments` and `data` must be of the same length
+func RenderMatrix(environments []string, data []*processing.ConsolidatedJson, templatesDir string) (string, error) {
+	matrix := newMatrix(environments, data)
+
+	templates := templatesFS(templatesDir)
+	templatePathName := path.Join(templatesRoot, matrixTemplate)
+
+	if _, err := fs.Stat(templates, templatePathName); err != nil {
+		return "", err
+	}
+
+	matrixTmpl, err := template.New(matrixTemplate).Funcs(templateFuncs()).ParseFS(templates, templatePathName)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+	if err := matrixTmpl.Execute(&output, matrix); err != nil {
+		return "", err
+	}
+
+	return output.String(), nil
+}
+
+func newMatrix(environments []string, data []*processing.ConsolidatedJson) *matrixData {
+	matrix := &matrixData{Environments: environments}
+	rows := make(map[string]*MatrixRow)
+
+	row := func(module string) *MatrixRow {
+		if _, ok := rows[module]; !ok {
+			rows[module] = &MatrixRow{Module: module}
+			for _, environment := range environments {
+				rows[module].Cells = append(rows[module].Cells, &MatrixCell{Environment: environment})
+			}
+		}
+
+		return rows[module]
+	}
+
+	for i, envData := range data {
+		matrix.Summaries = append(matrix.Summaries, NewSummary(envData, time.Time{}))
+
+		for _, module := range envData.Modules {
+			row(module).Cells[i].Present = true
+		}
+
+		seen := make(map[*processing.ResourceData]bool)
+		for _, items := range [][]*processing.ResourceData{envData.Deleted, envData.Created, envData.Updated} {
+			for _, item := range items {
+				if seen[item] {
+					continue
+				}
+				seen[item] = true
+
+				cell := row(item.Module).Cells[i]
+				cell.Present = true
+				cell.Items = append(cell.Items, item)
+
+				switch {
+				case item.Actions.Replace():
+					cell.ToReplace++
+				case item.Actions.Create():
+					cell.ToAdd++
+				case item.Actions.Delete():
+					cell.ToDestroy++
+				default:
+					cell.ToChange++
+				}
+
+				if !item.IsAllowed() {
+					cell.Blocked++
+				}
+			}
+		}
+	}
+
+	for _, r := range rows {
+		for _, cell := range r.Cells {
+			slices.SortFunc(cell.Items, func(a, b *processing.ResourceData) int {
+				return cmp.Compare(a.Address, b.Address)
+			})
+
+			if cell.ToDestroy+cell.ToReplace == 0 {
+				continue
+			}
+
+			for _, other := range r.Cells {
+				if other.Present && other.ToDestroy+other.ToReplace == 0 {
+					cell.Divergent = true
+				}
+			}
+		}
+
+		matrix.Rows = append(matrix.Rows, r)
+	}
+
+	slices.SortFunc(matrix.Rows, func(a, b *MatrixRow) int {
+		return cmp.Compare(a.Module, b.Module)
+	})
+
+	return matrix
+}
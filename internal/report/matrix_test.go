@@ -0,0 +1,82 @@
+package report
+
+import (
+	"testing"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+type MatrixTestSuite struct {
+	suite.Suite
+
+	data []*processing.ConsolidatedJson
+	dm   *processing.DecisionMaker
+}
+
+func (ts *MatrixTestSuite) SetupTest() {
+	ts.dm = processing.NewDecisionMaker(&config.AppConfig{DefensePlan: config.DefensePlan{
+		ExceptionalResources: map[string]bool{"critical": true},
+	}})
+
+	change := func(address string, resourceType string, actions ...tfJson.Action) *tfJson.ResourceChange {
+		return &tfJson.ResourceChange{Address: address, Type: resourceType, Change: &tfJson.Change{Actions: actions}}
+	}
+
+	dev := new(processing.ConsolidatedJson)
+	dev.Parse("app", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		change("other.a", "other", tfJson.ActionCreate),
+		change("other.b", "other", tfJson.ActionUpdate),
+	}}, ts.dm)
+	dev.Parse("dev-only", &tfJson.Plan{}, ts.dm)
+
+	prod := new(processing.ConsolidatedJson)
+	prod.Parse("app", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		change("other.a", "other", tfJson.ActionCreate),
+		change("other.c", "other", tfJson.ActionDelete),
+		change("critical.d", "critical", tfJson.ActionDelete, tfJson.ActionCreate),
+	}}, ts.dm)
+
+	ts.data = []*processing.ConsolidatedJson{dev, prod}
+	for _, envData := range ts.data {
+		ts.dm.Evaluate(envData)
+	}
+}
+
+func (ts *MatrixTestSuite) TestCells() {
+	matrix := newMatrix([]string{"dev", "prod"}, ts.data)
+
+	assert.Len(ts.T(), matrix.Rows, 2)                 //nolint:typecheck
+	assert.Equal(ts.T(), "app", matrix.Rows[0].Module) //nolint:typecheck
+
+	dev, prod := matrix.Rows[0].Cells[0], matrix.Rows[0].Cells[1]
+	assert.Equal(ts.T(), "+1 ~1", dev.Counts())               //nolint:typecheck
+	assert.Equal(ts.T(), "+1 -1 ±1", prod.Counts())           //nolint:typecheck
+	assert.Equal(ts.T(), 1, prod.Blocked)                     //nolint:typecheck
+	assert.True(ts.T(), prod.Divergent)                       //nolint:typecheck
+	assert.False(ts.T(), dev.Divergent)                       //nolint:typecheck
+	assert.Len(ts.T(), prod.Items, 3)                         //nolint:typecheck
+	assert.Equal(ts.T(), "critical.d", prod.Items[0].Address) //nolint:typecheck
+
+	assert.Equal(ts.T(), "no changes", matrix.Rows[1].Cells[0].Counts()) //nolint:typecheck
+	assert.Equal(ts.T(), "n/a", matrix.Rows[1].Cells[1].Counts())        //nolint:typecheck
+}
+
+func (ts *MatrixTestSuite) TestRender() {
+	content, err := RenderMatrix([]string{"dev", "prod"}, ts.data, "")
+
+	assert.Nil(ts.T(), err)                                                     //nolint:typecheck
+	assert.Contains(ts.T(), content, "| Module | dev | prod |")                 //nolint:typecheck
+	assert.Contains(ts.T(), content, "| `app` | +1 ~1 | :no_entry: +1 -1 ±1 |") //nolint:typecheck
+	assert.Contains(ts.T(), content, "<summary>app / prod: +1 -1 ±1</summary>") //nolint:typecheck
+	assert.NotContains(ts.T(), content, "dev-only / dev")                       //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestMatrix(t *testing.T) {
+	suite.Run(t, new(MatrixTestSuite))
+}
@@ -0,0 +1,223 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// Max amount of removals listed in notifications, the rest are counted only
+	notificationMaxRemovals = 20
+	// https://api.slack.com/reference/block-kit/blocks#section
+	slackSectionTextLimit = 3000
+)
+
+var verdictIcons = map[string]string{
+	VerdictPassed:   "✅",
+	VerdictWarnings: "⚠️",
+	VerdictBlocked:  "⛔",
+}
+
+// forSlack function returns the report rendered to Slack Block Kit message payload
+func forSlack(output io.Writer) *report {
+	return &report{
+		output:   output,
+		renderer: renderSlack,
+		answers: map[bool]string{
+			true:  "yes",
+			false: "no",
+		},
+	}
+}
+
+// forTeams function returns the report rendered to Microsoft Teams message payload with Adaptive Card
+func forTeams(output io.Writer) *report {
+	return &report{
+		output:   output,
+		renderer: renderTeams,
+		answers: map[bool]string{
+			true:  "yes",
+			false: "no",
+		},
+	}
+}
+
+// removals function returns deleted resources split by decision: blocked and allowed (warned) ones. Removals accepted
+// by baseline are not listed
+func (r *report) removals() (blocked []*reportItem, warned []*reportItem) {
+	for _, section := range r.data {
+		if section.ActionType != deleted {
+			continue
+		}
+
+		for _, item := range section.Items {
+			if item.Accepted != nil {
+				continue
+			}
+
+			if item.Allowed {
+				warned = append(warned, item)
+			} else {
+				blocked = append(blocked, item)
+			}
+		}
+	}
+
+	return blocked, warned
+}
+
+// removalsList function returns compact list of removals, one per line, formatted by `line` function. The list is
+// cut with the row of amount of omitted removals, when it reaches max amount of removals or `sizeLimit` characters
+// (if the limit is not 0)
+func removalsList(items []*reportItem, sizeLimit int, line func(*reportItem) string) string {
+	var lines []string
+
+	size := 0
+	moreSize := textSize(fmt.Sprintf("\n… and %d more", len(items)))
+	for i, item := range items {
+		itemLine := line(item)
+
+		// The row of omitted removals has to fit as well, unless the item is the last one
+		reserved := 0
+		if i+1 < len(items) {
+			reserved = moreSize
+		}
+
+		if i == notificationMaxRemovals || (sizeLimit > 0 && size+textSize(itemLine)+reserved > sizeLimit) {
+			lines = append(lines, fmt.Sprintf("… and %d more", len(items)-i))
+			break
+		}
+
+		lines = append(lines, itemLine)
+		size += textSize(itemLine) + 1
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (s *Summary) footer() string {
+	return fmt.Sprintf("tf-plan-reporter %s · %s", s.Version, s.GeneratedAt.UTC().Format("2006-01-02 15:04:05 MST"))
+}
+
+// https://api.slack.com/reference/block-kit/blocks
+func renderSlack(r *report) (string, error) {
+	type text struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+
+	type block struct {
+		Type     string  `json:"type"`
+		Text     *text   `json:"text,omitempty"`
+		Elements []*text `json:"elements,omitempty"`
+	}
+
+	summary := r.summary
+	blocks := []*block{
+		{Type: "header", Text: &text{Type: "plain_text", Text: fmt.Sprintf("%s Terraform plan: %s", verdictIcons[summary.Verdict], strings.ToUpper(summary.Verdict))}},
+		{Type: "section", Text: &text{Type: "mrkdwn", Text: fmt.Sprintf("*%s*", summary.Plan())}},
+	}
+
+	blocked, warned := r.removals()
+	for _, removals := range []struct {
+		title string
+		items []*reportItem
+	}{
+		{":no_entry: *Blocked removals*", blocked},
+		{":warning: *Warned removals*", warned},
+	} {
+		if len(removals.items) == 0 {
+			continue
+		}
+
+		list := removalsList(removals.items, slackSectionTextLimit-textSize(removals.title)-1, func(item *reportItem) string {
+			if item.Approval != nil {
+				return fmt.Sprintf("• `%s` in `%s` (%s)", item.Address, item.Module, item.ApprovalNote())
+			}
+
+			return fmt.Sprintf("• `%s` in `%s`", item.Address, item.Module)
+		})
+
+		blocks = append(blocks, &block{Type: "section", Text: &text{Type: "mrkdwn", Text: removals.title + "\n" + list}})
+	}
+
+	blocks = append(blocks, &block{Type: "context", Elements: []*text{{Type: "mrkdwn", Text: summary.footer()}}})
+
+	payload, err := json.MarshalIndent(map[string]interface{}{
+		"text":   summary.Line(),
+		"blocks": blocks,
+	}, "", "  ")
+
+	return string(payload), err
+}
+
+// https://learn.microsoft.com/en-us/microsoftteams/platform/webhooks-and-connectors/how-to/connectors-using#send-adaptive-cards-using-an-incoming-webhook
+func renderTeams(r *report) (string, error) {
+	type element map[string]interface{}
+
+	summary := r.summary
+	colors := map[string]string{
+		VerdictPassed:   "Good",
+		VerdictWarnings: "Warning",
+		VerdictBlocked:  "Attention",
+	}
+
+	body := []element{
+		{"type": "TextBlock", "size": "Large", "weight": "Bolder", "color": colors[summary.Verdict], "wrap": true,
+			"text": fmt.Sprintf("%s Terraform plan: %s", verdictIcons[summary.Verdict], strings.ToUpper(summary.Verdict))},
+		{"type": "TextBlock", "text": summary.Plan(), "wrap": true},
+		{"type": "FactSet", "facts": []element{
+			{"title": "To add", "value": fmt.Sprint(summary.ToAdd)},
+			{"title": "To change", "value": fmt.Sprint(summary.ToChange)},
+			{"title": "To destroy", "value": fmt.Sprint(summary.ToDestroy)},
+			{"title": "To replace", "value": fmt.Sprint(summary.ToReplace)},
+			{"title": "Critical removals", "value": fmt.Sprint(summary.BlockedRemovals)},
+		}},
+	}
+
+	blocked, warned := r.removals()
+	for _, removals := range []struct {
+		title string
+		color string
+		items []*reportItem
+	}{
+		{"Blocked removals", "Attention", blocked},
+		{"Warned removals", "Warning", warned},
+	} {
+		if len(removals.items) == 0 {
+			continue
+		}
+
+		list := removalsList(removals.items, 0, func(item *reportItem) string {
+			if item.Approval != nil {
+				return fmt.Sprintf("- %s in %s (%s)", item.Address, item.Module, item.ApprovalNote())
+			}
+
+			return fmt.Sprintf("- %s in %s", item.Address, item.Module)
+		})
+
+		body = append(body,
+			element{"type": "TextBlock", "text": removals.title, "weight": "Bolder", "color": removals.color},
+			element{"type": "TextBlock", "text": list, "wrap": true},
+		)
+	}
+
+	body = append(body, element{"type": "TextBlock", "text": summary.footer(), "isSubtle": true, "size": "Small", "wrap": true})
+
+	payload, err := json.MarshalIndent(element{
+		"type": "message",
+		"attachments": []element{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": element{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    body,
+			},
+		}},
+	}, "", "  ")
+
+	return string(payload), err
+}
@@ -0,0 +1,142 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+type NotificationTestSuite struct {
+	suite.Suite
+
+	data *processing.ConsolidatedJson
+	dm   *processing.DecisionMaker
+}
+
+func (ts *NotificationTestSuite) SetupSuite() {
+	ts.dm = processing.NewDecisionMaker(&config.AppConfig{DefensePlan: config.DefensePlan{
+		ExceptionalResources: map[string]bool{"critical": true},
+	}})
+
+	var changes []*tfJson.ResourceChange
+	for i := 0; i < notificationMaxRemovals+5; i++ {
+		changes = append(changes, &tfJson.ResourceChange{
+			Address: fmt.Sprintf("critical.r%d", i),
+			Type:    "critical",
+			Change:  &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionDelete}},
+		})
+	}
+	changes = append(changes,
+		&tfJson.ResourceChange{Address: "allowed.r", Type: "allowed", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionDelete}}},
+		&tfJson.ResourceChange{Address: "created.r", Type: "created", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionCreate}}},
+	)
+
+	ts.data = new(processing.ConsolidatedJson)
+	ts.data.Parse("envs/prod", &tfJson.Plan{ResourceChanges: changes}, ts.dm)
+	ts.dm.Evaluate(ts.data)
+}
+
+func (ts *NotificationTestSuite) render(format string) map[string]interface{} {
+	r, err := newReport(format, nil, "")
+	assert.Nil(ts.T(), err) //nolint:typecheck
+
+	r.summary = NewSummary(ts.data, time.Now())
+	r.Prepare(ts.data)
+
+	parts, err := r.Render()
+	assert.Nil(ts.T(), err)      //nolint:typecheck
+	assert.Len(ts.T(), parts, 1) //nolint:typecheck
+
+	var payload map[string]interface{}
+	assert.Nil(ts.T(), json.Unmarshal([]byte(parts[0]), &payload)) //nolint:typecheck
+
+	return payload
+}
+
+func (ts *NotificationTestSuite) TestSlack() {
+	payload := ts.render("slack")
+	blocks := payload["blocks"].([]interface{})
+
+	assert.Contains(ts.T(), payload["text"], "Verdict: blocked") //nolint:typecheck
+	assert.Len(ts.T(), blocks, 5)                                //nolint:typecheck
+
+	header := blocks[0].(map[string]interface{})["text"].(map[string]interface{})
+	assert.Equal(ts.T(), "⛔ Terraform plan: BLOCKED", header["text"]) //nolint:typecheck
+
+	blocked := blocks[2].(map[string]interface{})["text"].(map[string]interface{})["text"].(string)
+	assert.Contains(ts.T(), blocked, "*Blocked removals*")             //nolint:typecheck
+	assert.Contains(ts.T(), blocked, "• `critical.r0` in `envs/prod`") //nolint:typecheck
+	assert.Contains(ts.T(), blocked, "… and 5 more")                   //nolint:typecheck
+
+	warned := blocks[3].(map[string]interface{})["text"].(map[string]interface{})["text"].(string)
+	assert.Contains(ts.T(), warned, "• `allowed.r` in `envs/prod`") //nolint:typecheck
+	assert.NotContains(ts.T(), warned, "created.r")                 //nolint:typecheck
+}
+
+// Removals with long addresses exceed the text limit of Slack section before reaching the max amount of them
+func (ts *NotificationTestSuite) TestSlackLongAddresses() {
+	var changes []*tfJson.ResourceChange
+	for i := 0; i < notificationMaxRemovals; i++ {
+		changes = append(changes, &tfJson.ResourceChange{
+			Address: fmt.Sprintf("module.%s.critical.r%d", strings.Repeat("nested", 40), i),
+			Type:    "critical",
+			Change:  &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionDelete}},
+		})
+	}
+
+	data := new(processing.ConsolidatedJson)
+	data.Parse("envs/prod", &tfJson.Plan{ResourceChanges: changes}, ts.dm)
+	ts.dm.Evaluate(data)
+
+	r, err := newReport("slack", nil, "")
+	assert.Nil(ts.T(), err) //nolint:typecheck
+
+	r.summary = NewSummary(data, time.Now())
+	r.Prepare(data)
+
+	parts, err := r.Render()
+	assert.Nil(ts.T(), err) //nolint:typecheck
+
+	var payload map[string]interface{}
+	assert.Nil(ts.T(), json.Unmarshal([]byte(parts[0]), &payload)) //nolint:typecheck
+
+	blocked := payload["blocks"].([]interface{})[2].(map[string]interface{})["text"].(map[string]interface{})["text"].(string)
+	listed := strings.Count(blocked, "• `module.")
+
+	assert.LessOrEqual(ts.T(), textSize(blocked), slackSectionTextLimit)                                          //nolint:typecheck
+	assert.Greater(ts.T(), listed, 0)                                                                             //nolint:typecheck
+	assert.Less(ts.T(), listed, notificationMaxRemovals)                                                          //nolint:typecheck
+	assert.True(ts.T(), strings.HasSuffix(blocked, fmt.Sprintf("… and %d more", notificationMaxRemovals-listed))) //nolint:typecheck
+}
+
+func (ts *NotificationTestSuite) TestTeams() {
+	payload := ts.render("teams")
+	attachment := payload["attachments"].([]interface{})[0].(map[string]interface{})
+	card := attachment["content"].(map[string]interface{})
+	body := card["body"].([]interface{})
+
+	assert.Equal(ts.T(), "application/vnd.microsoft.card.adaptive", attachment["contentType"]) //nolint:typecheck
+	assert.Equal(ts.T(), "AdaptiveCard", card["type"])                                         //nolint:typecheck
+	assert.Equal(ts.T(), "Attention", body[0].(map[string]interface{})["color"])               //nolint:typecheck
+
+	facts := body[2].(map[string]interface{})["facts"].([]interface{})
+	assert.Contains(ts.T(), facts, map[string]interface{}{"title": "Critical removals", "value": "25"}) //nolint:typecheck
+
+	blocked := body[4].(map[string]interface{})["text"].(string)
+	assert.Contains(ts.T(), blocked, "- critical.r0 in envs/prod") //nolint:typecheck
+	assert.Contains(ts.T(), blocked, "… and 5 more")               //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestNotification(t *testing.T) {
+	suite.Run(t, new(NotificationTestSuite))
+}
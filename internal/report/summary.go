@@ -0,0 +1,140 @@
+package report
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+	"github.com/arshvin/tf-plan-reporter/internal/version"
+)
+
+const (
+	VerdictPassed   = "passed"
+	VerdictWarnings = "warnings"
+	VerdictBlocked  = "blocked"
+)
+
+// Summary is the overall summary of planned changes. It's passed to `<format>/summary.tmpl` template rendered on top of
+// the report, and is accessible as `.Summary` in whole document templates
+type Summary struct {
+	ToAdd                 int           `json:"to_add"`                  //Amount of created resources, excluding replaced ones
+	ToChange              int           `json:"to_change"`               //Amount of updated resources
+	ToDestroy             int           `json:"to_destroy"`              //Amount of deleted resources, excluding replaced ones
+	ToReplace             int           `json:"to_replace"`              //Amount of replaced (deleted and created) resources
+	Unchanged             int           `json:"unchanged"`               //Amount of unchanged resources, including suppressed ones
+	SuppressedUpdates     int           `json:"suppressed_updates"`      //Amount of updates of ignored attributes only, reported as unchanged
+	ToRead                int           `json:"to_read"`                 //Amount of data sources to be read
+	Modules               []string      `json:"modules"`                 //All parsed modules
+	ModulesWithoutChanges []string      `json:"modules_without_changes"` //Modules without any planned changes
+	BlockedRemovals       int           `json:"blocked_removals"`        //Amount of deleted resources, which are not allowed to be removed
+	AllowedRemovals       int           `json:"allowed_removals"`        //Amount of deleted resources, which are allowed to be removed
+	ApprovedRemovals      int           `json:"approved_removals"`       //Amount of deleted resources, whose removals are approved
+	AcceptedChanges       int           `json:"accepted_changes"`        //Amount of planned changes accepted by baseline
+	ExpiredAcceptances    []string      `json:"expired_acceptances"`     //Descriptions of expired baseline entries
+	Verdict               string        `json:"verdict"`                 //Result of the gate: passed, warnings (there are allowed or approved removals, or expired baseline entries) or blocked
+	Version               string        `json:"version"`                 //Version of the reporter
+	GeneratedAt           time.Time     `json:"generated_at"`
+	Duration              time.Duration `json:"-"` //Time elapsed since the start of the run
+}
+
+// NewSummary function counts planned changes and the verdicts of the gate over deleted resources of the evaluated data
+func NewSummary(data *processing.ConsolidatedJson, startedAt time.Time) *Summary {
+	summary := &Summary{
+		ToChange:          len(data.Updated),
+		Unchanged:         len(data.Unchanged),
+		SuppressedUpdates: data.SuppressedItems(),
+		ToRead:            len(data.Read),
+		Modules:           slices.Clone(data.Modules),
+		Version:           version.Version,
+	}
+
+	for _, item := range data.Created {
+		if !item.Actions.Replace() {
+			summary.ToAdd++
+		}
+	}
+
+	changedModules := make(map[string]bool)
+	for _, items := range [][]*processing.ResourceData{data.Created, data.Updated, data.Deleted} {
+		for _, item := range items {
+			changedModules[item.Module] = true
+		}
+	}
+
+	//Replaced resources are both in created and deleted ones, they are counted once
+	seen := make(map[*processing.ResourceData]bool)
+	for _, items := range [][]*processing.ResourceData{data.Created, data.Updated, data.Deleted} {
+		for _, item := range items {
+			if !seen[item] && item.AcceptedBy() != nil {
+				summary.AcceptedChanges++
+			}
+			seen[item] = true
+		}
+	}
+	summary.ExpiredAcceptances = slices.Clone(data.ExpiredAcceptances)
+
+	for _, item := range data.Deleted {
+		if item.Actions.Replace() {
+			summary.ToReplace++
+		} else {
+			summary.ToDestroy++
+		}
+
+		if item.AcceptedBy() != nil {
+			continue
+		}
+
+		if item.ApprovedBy() != nil {
+			summary.ApprovedRemovals++
+			continue
+		}
+
+		if item.IsAllowed() {
+			summary.AllowedRemovals++
+		} else {
+			summary.BlockedRemovals++
+		}
+	}
+
+	for _, module := range summary.Modules {
+		if !changedModules[module] {
+			summary.ModulesWithoutChanges = append(summary.ModulesWithoutChanges, module)
+		}
+	}
+	slices.Sort(summary.Modules)
+	slices.Sort(summary.ModulesWithoutChanges)
+
+	switch {
+	case summary.BlockedRemovals > 0:
+		summary.Verdict = VerdictBlocked
+	case summary.AllowedRemovals > 0 || summary.ApprovedRemovals > 0 || len(summary.ExpiredAcceptances) > 0:
+		summary.Verdict = VerdictWarnings
+	default:
+		summary.Verdict = VerdictPassed
+	}
+
+	summary.GeneratedAt = time.Now()
+	if !startedAt.IsZero() {
+		summary.Duration = summary.GeneratedAt.Sub(startedAt).Round(time.Millisecond)
+	}
+
+	return summary
+}
+
+// Plan function returns the counts of planned changes in the way terraform does it
+func (s *Summary) Plan() string {
+	return fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy, %d to replace across %d %s",
+		s.ToAdd, s.ToChange, s.ToDestroy, s.ToReplace, len(s.Modules), plural(len(s.Modules), "module", "modules"))
+}
+
+// Line function returns the summary as a single line, e.g. for CI logs
+func (s *Summary) Line() string {
+	line := fmt.Sprintf("%s. Verdict: %s", s.Plan(), s.Verdict)
+
+	if s.BlockedRemovals > 0 {
+		line += fmt.Sprintf(" (%d critical %s)", s.BlockedRemovals, plural(s.BlockedRemovals, "removal", "removals"))
+	}
+
+	return line
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+type SummaryTestSuite struct {
+	suite.Suite
+
+	data *processing.ConsolidatedJson
+}
+
+func (ts *SummaryTestSuite) SetupTest() {
+	change := func(address string, resourceType string, actions ...tfJson.Action) *tfJson.ResourceChange {
+		return &tfJson.ResourceChange{Address: address, Type: resourceType, Change: &tfJson.Change{Actions: actions}}
+	}
+
+	ts.data = new(processing.ConsolidatedJson)
+	ts.data.Parse("a", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		change("r1.a", "resource1", tfJson.ActionCreate),
+		change("r1.b", "resource1", tfJson.ActionCreate),
+		change("r2.a", "resource2", tfJson.ActionDelete, tfJson.ActionCreate),
+		change("r3.a", "resource3", tfJson.ActionUpdate),
+	}}, nil)
+	ts.data.Parse("b", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		change("r4.a", "resource4", tfJson.ActionNoop),
+	}}, nil)
+	ts.data.Parse("c", &tfJson.Plan{ResourceChanges: []*tfJson.ResourceChange{
+		change("r1.c", "resource1", tfJson.ActionDelete),
+	}}, nil)
+}
+
+func (ts *SummaryTestSuite) evaluate(critical ...string) {
+	settings := &config.AppConfig{DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{}}}
+	for _, item := range critical {
+		settings.ExceptionalResources[item] = true
+	}
+
+	processing.NewDecisionMaker(settings).Evaluate(ts.data)
+}
+
+func (ts *SummaryTestSuite) TestCounts() {
+	ts.evaluate()
+	summary := NewSummary(ts.data, time.Now())
+
+	assert.Equal(ts.T(), "Plan: 2 to add, 1 to change, 1 to destroy, 1 to replace across 3 modules", summary.Plan()) //nolint:typecheck
+	assert.Equal(ts.T(), []string{"b"}, summary.ModulesWithoutChanges)                                               //nolint:typecheck
+	assert.Equal(ts.T(), 1, summary.Unchanged)                                                                       //nolint:typecheck
+}
+
+func (ts *SummaryTestSuite) TestVerdictWarnings() {
+	ts.evaluate("resource3")
+	summary := NewSummary(ts.data, time.Time{})
+
+	assert.Equal(ts.T(), VerdictWarnings, summary.Verdict) //nolint:typecheck
+	assert.Equal(ts.T(), 2, summary.AllowedRemovals)       //nolint:typecheck
+}
+
+func (ts *SummaryTestSuite) TestVerdictBlocked() {
+	ts.evaluate("resource2")
+	summary := NewSummary(ts.data, time.Time{})
+
+	assert.Equal(ts.T(), VerdictBlocked, summary.Verdict)                                                                                                   //nolint:typecheck
+	assert.Equal(ts.T(), "Plan: 2 to add, 1 to change, 1 to destroy, 1 to replace across 3 modules. Verdict: blocked (1 critical removal)", summary.Line()) //nolint:typecheck
+}
+
+func (ts *SummaryTestSuite) TestVerdictPassed() {
+	data := new(processing.ConsolidatedJson)
+	data.Parse("a", &tfJson.Plan{}, nil)
+	processing.NewDecisionMaker(&config.AppConfig{}).Evaluate(data)
+
+	assert.Equal(ts.T(), VerdictPassed, NewSummary(data, time.Time{}).Verdict) //nolint:typecheck
+}
+
+func (ts *SummaryTestSuite) TestAcceptedRemovals() {
+	processing.NewDecisionMaker(&config.AppConfig{
+		DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{"resource1": true, "resource2": true}},
+		Baseline: config.Baseline{Accepted: []*config.AcceptedChange{
+			{Address: "r1.c", Action: "delete"},
+			{Address: "r2.a", Action: "replace"},
+		}},
+	}).Evaluate(ts.data)
+	summary := NewSummary(ts.data, time.Time{})
+
+	assert.Equal(ts.T(), VerdictPassed, summary.Verdict) //nolint:typecheck
+	assert.Equal(ts.T(), 2, summary.AcceptedChanges)     //nolint:typecheck
+	assert.Equal(ts.T(), 0, summary.BlockedRemovals)     //nolint:typecheck
+	assert.Equal(ts.T(), 0, summary.AllowedRemovals)     //nolint:typecheck
+}
+
+func (ts *SummaryTestSuite) TestVerdictExpiredAcceptance() {
+	processing.NewDecisionMaker(&config.AppConfig{
+		DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{"resource1": true, "resource2": true}},
+		Baseline: config.Baseline{Accepted: []*config.AcceptedChange{
+			{Address: "r1.c", Action: "delete"},
+			{Address: "r2.a", Action: "replace"},
+			{Address: "r9.a", Action: "delete", Expires: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}},
+	}).Evaluate(ts.data)
+	summary := NewSummary(ts.data, time.Time{})
+
+	assert.Equal(ts.T(), VerdictWarnings, summary.Verdict)                                                        //nolint:typecheck
+	assert.Equal(ts.T(), []string{"delete acceptance of r9.a expired on 2020-01-01"}, summary.ExpiredAcceptances) //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestSummary(t *testing.T) {
+	suite.Run(t, new(SummaryTestSuite))
+}
@@ -0,0 +1,184 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/alexeyco/simpletable"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	templatesRoot = "templates"
+)
+
+var builtinFormats = map[string]func(io.Writer) *report{
+	"github_markdown":       forGitHub,
+	"gitlab_markdown":       forGitLab,
+	"azure_devops_markdown": forAzureDevOps,
+	"bitbucket_markdown":    forBitbucket,
+	"stdout": func(output io.Writer) *report {
+		r := forStdout()
+		r.output = output
+
+		return r
+	},
+	"html":  forHtml,
+	"json":  forJson,
+	"slack": forSlack,
+	"teams": forTeams,
+}
+
+// overlayFS is the file system, which looks for templates in user supplied folder first and falls back to
+// embedded templates if the file was not found there
+type overlayFS struct {
+	userDir  fs.FS
+	embedded fs.FS
+}
+
+func (o *overlayFS) Open(name string) (fs.File, error) {
+	if relName, ok := strings.CutPrefix(name, templatesRoot+"/"); ok {
+		if file, err := o.userDir.Open(relName); err == nil {
+			log.WithField("template", name).Debug("The template has been taken from user supplied folder")
+
+			return file, nil
+		}
+	}
+
+	return o.embedded.Open(name)
+}
+
+// templatesFS function returns the file system of report templates, taking into account user supplied folder, if any
+func templatesFS(templatesDir string) fs.FS {
+	if len(templatesDir) == 0 {
+		return content
+	}
+
+	return &overlayFS{
+		userDir:  os.DirFS(templatesDir),
+		embedded: content,
+	}
+}
+
+// newReport function instantiates report of the built-in or user supplied format. The format of report is a template
+// named `<format>.tmpl`, which is rendered either with overlaying by `<format>/<action type>.tmpl` templates for every
+// action type section, or as a whole document if there are no such section templates
+func newReport(format string, output io.Writer, templatesDir string) (*report, error) {
+	templates := templatesFS(templatesDir)
+
+	if constructor, ok := builtinFormats[format]; ok {
+		r := constructor(output)
+		r.templates = templates
+
+		return r, nil
+	}
+
+	r := &report{
+		output:    output,
+		templates: templates,
+		template:  path.Join(templatesRoot, format+".tmpl"),
+		answers: map[bool]string{
+			true:  "yes",
+			false: "no",
+		},
+		tableStyle: simpletable.StyleMarkdown,
+	}
+
+	if _, err := fs.Stat(templates, r.template); err != nil {
+		return nil, fmt.Errorf("unknown report format '%s': %w", format, err)
+	}
+
+	if _, err := fs.Stat(templates, r.getTemplate("deleted.tmpl")); errors.Is(err, fs.ErrNotExist) {
+		r.wholeDocument = true
+	}
+
+	return r, nil
+}
+
+// templateFuncs function returns helper functions available in all report templates
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"title":     title,
+		"join":      func(sep string, items []string) string { return strings.Join(items, sep) },
+		"replace":   func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"trim":      strings.TrimSpace,
+		"contains":  func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix": func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix": func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"repeat":    func(count int, s string) string { return strings.Repeat(s, count) },
+		"truncate":  truncate,
+		"plural":    plural,
+		"default":   defaultValue,
+		"add":       func(a, b int) int { return a + b },
+		"sub":       func(a, b int) int { return a - b },
+		"toJson":    toJson,
+		"mdEscape":  markdownEscape,
+	}
+}
+
+// title function makes the first letter of every word upper case
+func title(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		words[i] = strings.ToUpper(string(r)) + word[size:]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// truncate function cuts the string to specified length adding ellipsis, if the string is longer
+func truncate(length int, s string) string {
+	if utf8.RuneCountInString(s) <= length {
+		return s
+	}
+
+	return string([]rune(s)[:length]) + "…"
+}
+
+// plural function returns singular or plural form depending on the count, e.g. {{ plural .ItemCount "resource" "resources" }}
+func plural(count int, singular, pluralForm string) string {
+	if count == 1 {
+		return singular
+	}
+
+	return pluralForm
+}
+
+// defaultValue function returns the given value, or default one if the value is empty string
+func defaultValue(defaultVal, value string) string {
+	if len(value) == 0 {
+		return defaultVal
+	}
+
+	return value
+}
+
+func toJson(value interface{}) (string, error) {
+	encoded, err := json.Marshal(value)
+
+	return string(encoded), err
+}
+
+// markdownEscape function escapes symbols which have special meaning inside of markdown table cells
+func markdownEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		"|", `\|`,
+		"*", `\*`,
+		"_", `\_`,
+		"`", "\\`",
+		"<", "&lt;",
+		">", "&gt;",
+	).Replace(s)
+}
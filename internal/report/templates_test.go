@@ -0,0 +1,87 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+type TemplatesTestSuite struct {
+	suite.Suite
+
+	tmpDir string
+	data   *processing.ConsolidatedJson
+	dm     *processing.DecisionMaker
+}
+
+func (ts *TemplatesTestSuite) SetupSuite() {
+	ts.tmpDir = ts.T().TempDir() //nolint:typecheck
+
+	if err := os.MkdirAll(path.Join(ts.tmpDir, "stdout"), 0750); err != nil {
+		assert.FailNow(ts.T(), "Could not create folder for test: %s", ts.tmpDir) //nolint:typecheck
+	}
+
+	files := map[string]string{
+		"stdout/deleted.tmpl": `{{ define "caption" }}GONE: {{ .ItemCount }}{{ end }}`,
+		"brief.tmpl":          `{{ range .Modules }}{{ .Name | upper }}{{ range .Items }} {{ .Address }}={{ .ActionLabel }}{{ end }}{{ end }}`,
+	}
+	for name, fileContent := range files {
+		if err := os.WriteFile(path.Join(ts.tmpDir, name), []byte(fileContent), 0640); err != nil {
+			assert.FailNow(ts.T(), "Could not create file for test: %s", name) //nolint:typecheck
+		}
+	}
+
+	ts.dm = processing.NewDecisionMaker(&config.AppConfig{DefensePlan: config.DefensePlan{ExceptionalResources: map[string]bool{}}})
+
+	ts.data = new(processing.ConsolidatedJson)
+	ts.data.Parse("mod", &tfJson.Plan{
+		ResourceChanges: []*tfJson.ResourceChange{
+			{Address: "null_resource.a", Type: "null_resource", Name: "a", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionDelete}}},
+		},
+	}, ts.dm)
+	ts.dm.Evaluate(ts.data)
+}
+
+func (ts *TemplatesTestSuite) TestOverriddenSectionTemplate() {
+	var output bytes.Buffer
+
+	r, err := newReport("stdout", &output, ts.tmpDir)
+	assert.Nil(ts.T(), err) //nolint:typecheck
+
+	r.Prepare(ts.data)
+	assert.Nil(ts.T(), r.Print()) //nolint:typecheck
+
+	assert.Contains(ts.T(), output.String(), "GONE: 1") //nolint:typecheck
+}
+
+func (ts *TemplatesTestSuite) TestUserSuppliedFormat() {
+	var output bytes.Buffer
+
+	r, err := newReport("brief", &output, ts.tmpDir)
+	assert.Nil(ts.T(), err)              //nolint:typecheck
+	assert.True(ts.T(), r.wholeDocument) //nolint:typecheck
+
+	r.Prepare(ts.data)
+	assert.Nil(ts.T(), r.Print()) //nolint:typecheck
+
+	assert.Equal(ts.T(), "MOD null_resource.a=delete", output.String()) //nolint:typecheck
+}
+
+func (ts *TemplatesTestSuite) TestUnknownFormat() {
+	_, err := newReport("unknown", &bytes.Buffer{}, ts.tmpDir)
+
+	assert.ErrorContains(ts.T(), err, "unknown report format 'unknown'") //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestTemplates(t *testing.T) {
+	suite.Run(t, new(TemplatesTestSuite))
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+	"github.com/arshvin/tf-plan-reporter/internal/report"
+)
+
+const (
+	defaultModuleName   = "plan"
+	defaultReportFormat = "json"
+)
+
+// readPlans function reads the plans from the request body. The body is either a single plan JSON (the output of
+// `terraform show -json`), whose module name might be given by `module` query parameter, or an object with many of
+// them: {"plans": {"<module>": <plan JSON>, ...}}
+func (s *Server) readPlans(w http.ResponseWriter, r *http.Request) (map[string]*tfJson.Plan, int, error) {
+	module := r.URL.Query().Get("module")
+	if len(module) == 0 {
+		module = defaultModuleName
+	}
+
+	// The plans are decoded while the body is streamed, since they might take hundreds of MB
+	plans, err := processing.DecodePlans(http.MaxBytesReader(w, r.Body, s.options.MaxRequestSize), module)
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds the limit of %d bytes", maxBytesError.Limit)
+		}
+
+		return nil, http.StatusBadRequest, fmt.Errorf("could not parse plans: %w", err)
+	}
+
+	return plans, http.StatusOK, nil
+}
+
+// handleReport function renders the report of the uploaded plans in the format given by `format` query parameter,
+// JSON report by default
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	plans, status, err := s.readPlans(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if len(format) == 0 {
+		format = defaultReportFormat
+	}
+
+	body, err := s.render(plans, format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType(format))
+	_, _ = io.WriteString(w, body)
+}
+
+// handleVerdict function returns the summary of the uploaded plans with the gate verdict. The status code is 200 even
+// for `blocked` verdict, since the request itself has succeeded
+func (s *Server) handleVerdict(w http.ResponseWriter, r *http.Request) {
+	plans, status, err := s.readPlans(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	_, summary := s.evaluate(plans)
+
+	writeJson(w, http.StatusOK, summary)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReadiness function reports whether the policy is loaded. The server stays ready, if reload of the changed
+// config file has failed, since the previous policy is kept in use, but the error is shown
+func (s *Server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	s.configLock.Lock()
+	defer s.configLock.Unlock()
+
+	if s.settings == nil {
+		writeJson(w, http.StatusServiceUnavailable, map[string]string{"status": "config is not loaded"})
+		return
+	}
+
+	response := map[string]string{
+		"status":           "ready",
+		"config_loaded_at": s.configLoadedAt.Format(time.RFC3339),
+	}
+	if s.configError != nil {
+		response["config_error"] = s.configError.Error()
+	}
+
+	writeJson(w, http.StatusOK, response)
+}
+
+// render function evaluates the plans and renders the report of the format
+func (s *Server) render(plans map[string]*tfJson.Plan, format string) (string, error) {
+	settings, dm := s.policy()
+
+	return report.RenderBody(parse(plans, dm), settings, format, 0)
+}
+
+func contentType(format string) string {
+	switch {
+	case format == "json" || format == "slack" || format == "teams":
+		return "application/json"
+	case format == "html":
+		return "text/html; charset=utf-8"
+	case strings.HasSuffix(format, "markdown"):
+		return "text/markdown; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+func writeJson(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.WithError(err).Error("Could not write response")
+	}
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/arshvin/tf-plan-reporter/internal/publish"
+)
+
+const (
+	runTaskRequestSizeLimit = 1024 * 1024
+	runTaskSignatureHeader  = "X-TFC-Task-Signature"
+
+	// Terraform Cloud sends the request with this token, when the run task is created or updated, to verify the endpoint
+	runTaskVerificationToken = "test-token"
+)
+
+// runTaskRequest is the part of run task request payload, which is used by the App
+// https://developer.hashicorp.com/terraform/enterprise/api-docs/run-tasks/run-tasks-integration#run-task-request
+type runTaskRequest struct {
+	PayloadVersion        int    `json:"payload_version"`
+	AccessToken           string `json:"access_token"`
+	Stage                 string `json:"stage"`
+	TaskResultId          string `json:"task_result_id"`
+	TaskResultCallbackUrl string `json:"task_result_callback_url"`
+	PlanJsonApiUrl        string `json:"plan_json_api_url"`
+	RunId                 string `json:"run_id"`
+	WorkspaceName         string `json:"workspace_name"`
+	OrganizationName      string `json:"organization_name"`
+}
+
+// handleRunTask function accepts the run task request and processes it in background, since Terraform Cloud expects
+// the response within 10 seconds, while the result is sent to the callback url
+func (s *Server) handleRunTask(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, runTaskRequestSizeLimit))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if !s.verifySignature(body, r.Header.Get(runTaskSignatureHeader)) {
+		log.Warn("Run task request with invalid signature has been rejected")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var request runTaskRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if request.AccessToken == runTaskVerificationToken {
+		log.Info("Run task verification request has been received")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if len(request.TaskResultCallbackUrl) == 0 || len(request.AccessToken) == 0 {
+		http.Error(w, "task_result_callback_url and access_token are required", http.StatusBadRequest)
+		return
+	}
+
+	// The access token is sent to these URLs, so only Terraform Cloud ones are requested
+	for _, target := range []string{request.TaskResultCallbackUrl, request.PlanJsonApiUrl} {
+		if len(target) > 0 && !s.isTfcUrl(target) {
+			log.WithField("url", target).Warn("Run task request with URL of other host than Terraform Cloud has been rejected")
+			http.Error(w, fmt.Sprintf("URLs of the request must point to %s", s.options.TfcAddress), http.StatusBadRequest)
+			return
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"run_id":       request.RunId,
+		"workspace":    request.WorkspaceName,
+		"organization": request.OrganizationName,
+		"stage":        request.Stage,
+	}).Info("Run task request has been received")
+
+	s.tasks.Add(1)
+	go func() {
+		defer s.tasks.Done()
+		s.processRunTask(&request)
+	}()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifySignature function checks HMAC-SHA512 signature of the request body. Without the HMAC key requests are
+// accepted only if it's explicitly allowed
+func (s *Server) verifySignature(body []byte, signature string) bool {
+	if len(s.options.HmacKey) == 0 {
+		return s.options.InsecureRunTask
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha512.New, []byte(s.options.HmacKey))
+	mac.Write(body)
+
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// isTfcUrl function checks if the URL points to the configured Terraform Cloud address
+func (s *Server) isTfcUrl(target string) bool {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+
+	return parsed.Scheme == s.tfc.Scheme && strings.EqualFold(parsed.Host, s.tfc.Host)
+}
+
+func (s *Server) processRunTask(request *runTaskRequest) {
+	requestContext := log.WithField("run_id", request.RunId)
+	client := publish.NewTfcRunTaskClient(request.AccessToken, s.options.MaxPlanSize)
+
+	result, err := s.runTaskResult(client, request)
+	if err != nil {
+		requestContext.Error(err)
+		result = &publish.TfcTaskResult{
+			Status:  publish.TfcTaskFailed,
+			Message: fmt.Sprintf("The plan could not be evaluated: %s", err),
+		}
+	}
+
+	if err := client.SendResult(request.TaskResultCallbackUrl, result); err != nil {
+		requestContext.WithError(err).Error("Could not send run task result")
+		return
+	}
+
+	requestContext.WithField("status", result.Status).Info("Run task result has been sent")
+}
+
+// runTaskResult function downloads the plan of the run and evaluates the removal policy over it
+func (s *Server) runTaskResult(client *publish.TfcRunTaskClient, request *runTaskRequest) (*publish.TfcTaskResult, error) {
+	if len(request.PlanJsonApiUrl) == 0 {
+		return nil, fmt.Errorf("plan JSON is not available at '%s' stage, the run task should be attached to 'post_plan' stage", request.Stage)
+	}
+
+	plan, err := client.DownloadPlan(request.PlanJsonApiUrl)
+	if err != nil {
+		return nil, errors.Join(errors.New("could not download plan JSON"), err)
+	}
+
+	data, summary := s.evaluate(map[string]*tfJson.Plan{request.WorkspaceName: plan})
+
+	result := &publish.TfcTaskResult{
+		Status:  publish.TfcTaskPassed,
+		Message: summary.Line(),
+	}
+	if summary.BlockedRemovals > 0 {
+		result.Status = publish.TfcTaskFailed
+	}
+
+	for _, item := range data.Deleted {
+		status, level, decision := "Blocked", "error", "forbidden by the policy"
+		if accepted := item.AcceptedBy(); accepted != nil {
+			status, level, decision = "Accepted", "info", "accepted by baseline: "+accepted.Justification
+		} else if approval := item.ApprovedBy(); approval != nil {
+			status, level, decision = "Approved", "warning", fmt.Sprintf("approved by %s: %s", approval.Approver, approval.Reason)
+		} else if item.IsAllowed() {
+			status, level, decision = "Allowed", "warning", "allowed by the policy"
+		}
+
+		action := "deleted"
+		if item.Actions.Replace() {
+			action = "replaced"
+		}
+
+		result.Outcomes = append(result.Outcomes, &publish.TfcTaskOutcome{
+			Id:          item.Address,
+			Description: fmt.Sprintf("%s is going to be %s", item.Address, action),
+			Body: fmt.Sprintf("Resource `%s` of type `%s` is going to be %s. Its removal is %s.",
+				item.Address, item.Type, action, decision),
+			Tags: map[string][]publish.TfcTag{
+				"Status": {{Label: status, Level: level}},
+				"Type":   {{Label: item.Type}},
+			},
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,226 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+)
+
+const testPlan = `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "azurerm_key_vault.main", "type": "azurerm_key_vault", "name": "main", "change": {"actions": ["delete"]}},
+    {"address": "null_resource.a", "type": "null_resource", "name": "a", "change": {"actions": ["delete", "create"]}},
+    {"address": "null_resource.b", "type": "null_resource", "name": "b", "change": {"actions": ["create"]}}
+  ]
+}`
+
+// tfcMock is the local stand-in of Terraform Cloud API, which serves the plan JSON and receives the task result
+type tfcMock struct {
+	plan     string
+	callback map[string]interface{}
+	headers  http.Header
+}
+
+func (m *tfcMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer run-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/plans/plan-1/json-output":
+		_, _ = io.WriteString(w, m.plan)
+	case r.Method == http.MethodPatch && r.URL.Path == "/api/v2/task-results/taskrs-1/callback":
+		m.headers = r.Header
+		_ = json.NewDecoder(r.Body).Decode(&m.callback)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+type RunTaskTestSuite struct {
+	suite.Suite
+
+	mock *tfcMock
+	tfc  *httptest.Server
+}
+
+func (ts *RunTaskTestSuite) SetupTest() {
+	ts.mock = &tfcMock{plan: testPlan}
+	ts.tfc = httptest.NewServer(ts.mock)
+}
+
+func (ts *RunTaskTestSuite) TearDownTest() {
+	ts.tfc.Close()
+}
+
+// server function returns the server, which requests the mock as Terraform Cloud. Not signed requests are accepted,
+// if the HMAC key is empty
+func (ts *RunTaskTestSuite) server(hmacKey string) *Server {
+	return newTestServer(ts.T(), Options{HmacKey: hmacKey, InsecureRunTask: len(hmacKey) == 0, TfcAddress: ts.tfc.URL})
+}
+
+func (ts *RunTaskTestSuite) request(accessToken string) []byte {
+	body, _ := json.Marshal(map[string]interface{}{
+		"payload_version":          1,
+		"access_token":             accessToken,
+		"stage":                    "post_plan",
+		"task_result_id":           "taskrs-1",
+		"task_result_callback_url": ts.tfc.URL + "/api/v2/task-results/taskrs-1/callback",
+		"plan_json_api_url":        ts.tfc.URL + "/api/v2/plans/plan-1/json-output",
+		"run_id":                   "run-1",
+		"workspace_name":           "prod",
+	})
+
+	return body
+}
+
+func (ts *RunTaskTestSuite) send(s *Server, body []byte, signature string) int {
+	request := httptest.NewRequest(http.MethodPost, "/runtask", bytes.NewReader(body))
+	if len(signature) > 0 {
+		request.Header.Set(runTaskSignatureHeader, signature)
+	}
+
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, request)
+	s.tasks.Wait()
+
+	return recorder.Code
+}
+
+func (ts *RunTaskTestSuite) attributes() map[string]interface{} {
+	return ts.mock.callback["data"].(map[string]interface{})["attributes"].(map[string]interface{})
+}
+
+func (ts *RunTaskTestSuite) TestFailedOnCriticalRemoval() {
+	assert.Equal(ts.T(), http.StatusOK, ts.send(ts.server(""), ts.request("run-token"), "")) //nolint:typecheck
+
+	assert.Equal(ts.T(), "application/vnd.api+json", ts.mock.headers.Get("Content-Type")) //nolint:typecheck
+	assert.Equal(ts.T(), "failed", ts.attributes()["status"])                             //nolint:typecheck
+	assert.Contains(ts.T(), ts.attributes()["message"], "1 critical removal")             //nolint:typecheck
+
+	outcomes := ts.mock.callback["data"].(map[string]interface{})["relationships"].(map[string]interface{})["outcomes"].(map[string]interface{})["data"].([]interface{})
+	assert.Len(ts.T(), outcomes, 2) //nolint:typecheck
+
+	descriptions := map[string]string{}
+	for _, outcome := range outcomes {
+		attributes := outcome.(map[string]interface{})["attributes"].(map[string]interface{})
+		descriptions[attributes["outcome-id"].(string)] = attributes["description"].(string)
+	}
+	assert.Equal(ts.T(), "azurerm_key_vault.main is going to be deleted", descriptions["azurerm_key_vault.main"]) //nolint:typecheck
+	assert.Equal(ts.T(), "null_resource.a is going to be replaced", descriptions["null_resource.a"])              //nolint:typecheck
+}
+
+func (ts *RunTaskTestSuite) TestApprovedRemoval() {
+	s := ts.server("")
+	s.settings.Approvals = []*config.Approval{{Address: "azurerm_key_vault.main", Approver: "alice", Reason: "Approved in CHG-1234"}}
+	s.decisionMaker = processing.NewDecisionMaker(s.settings)
+
+	assert.Equal(ts.T(), http.StatusOK, ts.send(s, ts.request("run-token"), "")) //nolint:typecheck
+	assert.Equal(ts.T(), "passed", ts.attributes()["status"])                    //nolint:typecheck
+
+	outcomes := ts.mock.callback["data"].(map[string]interface{})["relationships"].(map[string]interface{})["outcomes"].(map[string]interface{})["data"].([]interface{})
+	for _, outcome := range outcomes {
+		attributes := outcome.(map[string]interface{})["attributes"].(map[string]interface{})
+		if attributes["outcome-id"] != "azurerm_key_vault.main" {
+			continue
+		}
+
+		status := attributes["tags"].(map[string]interface{})["Status"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(ts.T(), "Approved", status["label"])                                      //nolint:typecheck
+		assert.Contains(ts.T(), attributes["body"], "approved by alice: Approved in CHG-1234") //nolint:typecheck
+
+		return
+	}
+
+	assert.Fail(ts.T(), "Outcome of the approved removal is not found") //nolint:typecheck
+}
+
+func (ts *RunTaskTestSuite) TestPassed() {
+	ts.mock.plan = `{"format_version": "1.2", "resource_changes": [
+		{"address": "null_resource.b", "type": "null_resource", "name": "b", "change": {"actions": ["create"]}}
+	]}`
+
+	assert.Equal(ts.T(), http.StatusOK, ts.send(ts.server(""), ts.request("run-token"), "")) //nolint:typecheck
+	assert.Equal(ts.T(), "passed", ts.attributes()["status"])                                //nolint:typecheck
+}
+
+func (ts *RunTaskTestSuite) TestFailedOnDownloadError() {
+	ts.mock.plan = "not a json"
+
+	assert.Equal(ts.T(), http.StatusOK, ts.send(ts.server(""), ts.request("run-token"), "")) //nolint:typecheck
+	assert.Equal(ts.T(), "failed", ts.attributes()["status"])                                //nolint:typecheck
+	assert.Contains(ts.T(), ts.attributes()["message"], "could not download plan JSON")      //nolint:typecheck
+}
+
+func (ts *RunTaskTestSuite) TestFailedOnTooLargePlan() {
+	s := newTestServer(ts.T(), Options{MaxPlanSize: 64, InsecureRunTask: true, TfcAddress: ts.tfc.URL})
+
+	assert.Equal(ts.T(), http.StatusOK, ts.send(s, ts.request("run-token"), ""))         //nolint:typecheck
+	assert.Equal(ts.T(), "failed", ts.attributes()["status"])                            //nolint:typecheck
+	assert.Contains(ts.T(), ts.attributes()["message"], "exceeds the limit of 64 bytes") //nolint:typecheck
+}
+
+func (ts *RunTaskTestSuite) TestVerificationRequest() {
+	assert.Equal(ts.T(), http.StatusOK, ts.send(ts.server(""), ts.request(runTaskVerificationToken), "")) //nolint:typecheck
+	assert.Nil(ts.T(), ts.mock.callback)                                                                  //nolint:typecheck
+}
+
+func (ts *RunTaskTestSuite) TestSignature() {
+	body := ts.request("run-token")
+
+	mac := hmac.New(sha512.New, []byte("hmac-key"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	assert.Equal(ts.T(), http.StatusUnauthorized, ts.send(ts.server("hmac-key"), body, ""))     //nolint:typecheck
+	assert.Equal(ts.T(), http.StatusUnauthorized, ts.send(ts.server("other"), body, signature)) //nolint:typecheck
+	assert.Nil(ts.T(), ts.mock.callback)                                                        //nolint:typecheck
+
+	assert.Equal(ts.T(), http.StatusOK, ts.send(ts.server("hmac-key"), body, signature)) //nolint:typecheck
+	assert.Equal(ts.T(), "failed", ts.attributes()["status"])                            //nolint:typecheck
+}
+
+func (ts *RunTaskTestSuite) TestNotSignedRejectedByDefault() {
+	s := newTestServer(ts.T(), Options{TfcAddress: ts.tfc.URL})
+
+	assert.Equal(ts.T(), http.StatusUnauthorized, ts.send(s, ts.request("run-token"), "")) //nolint:typecheck
+	assert.Nil(ts.T(), ts.mock.callback)                                                   //nolint:typecheck
+}
+
+// The server must not send the access token anywhere but to Terraform Cloud
+func (ts *RunTaskTestSuite) TestOtherHostsRejected() {
+	other := httptest.NewServer(ts.mock)
+	defer other.Close()
+
+	for _, field := range []string{"task_result_callback_url", "plan_json_api_url"} {
+		var request map[string]interface{}
+		_ = json.Unmarshal(ts.request("run-token"), &request)
+		request[field] = other.URL + "/api/v2/plans/plan-1/json-output"
+		body, _ := json.Marshal(request)
+
+		assert.Equal(ts.T(), http.StatusBadRequest, ts.send(ts.server(""), body, "")) //nolint:typecheck
+	}
+	assert.Nil(ts.T(), ts.mock.callback) //nolint:typecheck
+
+	_, err := New(Options{ConfigFile: ts.server("").options.ConfigFile, TfcAddress: "app.terraform.io"})
+	assert.Error(ts.T(), err) //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestRunTask(t *testing.T) {
+	suite.Run(t, new(RunTaskTestSuite))
+}
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/arshvin/tf-plan-reporter/internal"
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+	"github.com/arshvin/tf-plan-reporter/internal/publish"
+	"github.com/arshvin/tf-plan-reporter/internal/report"
+)
+
+const (
+	readHeaderTimeout = 10 * time.Second
+
+	DefaultMaxRequestSize = 64 * 1024 * 1024
+	DefaultReloadInterval = 10 * time.Second
+	DefaultTfcAddress     = "https://app.terraform.io"
+)
+
+// Options of the server
+type Options struct {
+	ConfigFile      string        //Config file with the removal policy, it's reloaded on change
+	Profile         string        //Profile of the config file, if any
+	HmacKey         string        //Key run task requests must be signed with
+	InsecureRunTask bool          //Whether to accept not signed run task requests, if the HMAC key is empty
+	TfcAddress      string        //Address of Terraform Cloud / Enterprise, run tasks request its URLs only
+	MaxRequestSize  int64         //Max size of uploaded plans in bytes
+	MaxPlanSize     int64         //Max size of plans downloaded from Terraform Cloud in bytes
+	ReloadInterval  time.Duration //How often the config file is checked for changes
+}
+
+// Server evaluates terraform plans received over HTTP with the removal policy of the config file
+type Server struct {
+	options Options
+	tfc     *url.URL //Parsed TfcAddress option
+
+	configLock     sync.Mutex //Guards the loaded config and its decision maker, which are replaced by reloads
+	settings       *config.AppConfig
+	decisionMaker  *processing.DecisionMaker //Evaluates the plans by the policy of the loaded config
+	configModTimes map[string]time.Time      //Modification times of all files the config is loaded from
+	configError    error                     //Error of the last config reload, the previous config is kept in use then
+	configLoadedAt time.Time                 //Time of the last successful config load
+	tasks          sync.WaitGroup            //Run tasks being processed in background
+}
+
+// New function returns the server with the policy loaded from the config file
+func New(options Options) (*Server, error) {
+	if options.MaxRequestSize <= 0 {
+		options.MaxRequestSize = DefaultMaxRequestSize
+	}
+	if options.MaxPlanSize <= 0 {
+		options.MaxPlanSize = publish.DefaultMaxPlanSize
+	}
+	if options.ReloadInterval <= 0 {
+		options.ReloadInterval = DefaultReloadInterval
+	}
+	if len(options.TfcAddress) == 0 {
+		options.TfcAddress = DefaultTfcAddress
+	}
+
+	tfc, err := url.Parse(options.TfcAddress)
+	if err != nil || len(tfc.Scheme) == 0 || len(tfc.Host) == 0 {
+		return nil, fmt.Errorf("address of Terraform Cloud must be absolute URL, got '%s'", options.TfcAddress)
+	}
+
+	s := &Server{options: options, tfc: tfc}
+	if err := s.loadConfig(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /runtask", s.handleRunTask)
+	mux.HandleFunc("POST /api/v1/report", s.handleReport)
+	mux.HandleFunc("POST /api/v1/verdict", s.handleVerdict)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /readyz", s.handleReadiness)
+
+	return mux
+}
+
+func (s *Server) ListenAndServe(address string) error {
+	log.WithField("address", address).Info("Listening of HTTP requests")
+
+	go s.watchConfig()
+
+	server := &http.Server{
+		Addr:              address,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	return server.ListenAndServe()
+}
+
+// loadConfig function loads and validates the config file. The new policy is applied only if it's valid
+func (s *Server) loadConfig() error {
+	settings, err := config.LoadWithOptions(s.options.ConfigFile, config.Options{Profile: s.options.Profile})
+	if err != nil {
+		return err
+	}
+
+	if err := internal.ValidatePolicy(settings); err != nil {
+		return err
+	}
+
+	s.configLock.Lock()
+	defer s.configLock.Unlock()
+
+	s.settings = settings
+	s.decisionMaker = processing.NewDecisionMaker(settings)
+	s.configModTimes = modTimes(settings.SourceFiles())
+	s.configLoadedAt = time.Now()
+	s.configError = nil
+
+	return nil
+}
+
+// watchConfig function reloads the config whenever modification time of any file it's loaded from is changed: the config
+// file, included ones, per-directory overrides and the baseline, or a new override appears. Polling is used instead of file system events, because
+// config maps mounted to containers are replaced via symlinks
+func (s *Server) watchConfig() {
+	for range time.Tick(s.options.ReloadInterval) {
+		s.reloadConfigIfChanged()
+	}
+}
+
+func (s *Server) reloadConfigIfChanged() {
+	s.configLock.Lock()
+	loaded := s.configModTimes
+	searchFolder := s.settings.SearchFolder
+	s.configLock.Unlock()
+
+	files := slices.Collect(maps.Keys(loaded))
+
+	// Overrides might be added after the config is loaded, their appearance is a change too
+	overrides, err := config.FindOverrides(searchFolder)
+	if err != nil {
+		log.WithError(err).Warn("Could not look for per-directory overrides")
+	}
+	for _, override := range overrides {
+		if _, ok := loaded[override]; !ok {
+			files = append(files, override)
+		}
+	}
+
+	current := modTimes(files)
+	if maps.EqualFunc(loaded, current, time.Time.Equal) {
+		return
+	}
+
+	if err := s.loadConfig(); err != nil {
+		log.WithError(err).Error("Could not reload config file, the previous one is kept in use")
+
+		s.configLock.Lock()
+		s.configError = errors.Join(errors.New("could not reload config file"), err)
+		s.configModTimes = current //Not to retry until some file is changed again
+		s.configLock.Unlock()
+
+		return
+	}
+
+	log.WithField("config_file", s.options.ConfigFile).Info("Config file has been reloaded")
+}
+
+// modTimes function returns modification times of the files. Time of absent file is zero, so its appearance is
+// detected as a change too
+func modTimes(files []string) map[string]time.Time {
+	result := make(map[string]time.Time, len(files))
+	for _, file := range files {
+		if stat, err := os.Stat(file); err == nil {
+			result[file] = stat.ModTime()
+		} else {
+			result[file] = time.Time{}
+		}
+	}
+
+	return result
+}
+
+// policy function returns the config and the decision maker in use. The decision maker is never changed, so the
+// evaluations don't block each other and config reloads
+func (s *Server) policy() (*config.AppConfig, *processing.DecisionMaker) {
+	s.configLock.Lock()
+	defer s.configLock.Unlock()
+
+	return s.settings, s.decisionMaker
+}
+
+// evaluate function parses the plans of the modules and evaluates the removal policy over them
+func (s *Server) evaluate(plans map[string]*tfJson.Plan) (*processing.ConsolidatedJson, *report.Summary) {
+	_, dm := s.policy()
+
+	startedAt := time.Now()
+	data := parse(plans, dm)
+
+	return data, report.NewSummary(data, startedAt)
+}
+
+func parse(plans map[string]*tfJson.Plan, dm *processing.DecisionMaker) *processing.ConsolidatedJson {
+	data := new(processing.ConsolidatedJson)
+	for module, plan := range plans {
+		data.Parse(module, plan, dm)
+	}
+	dm.Evaluate(data)
+
+	return data
+}
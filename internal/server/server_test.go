@@ -0,0 +1,199 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/internal/report"
+)
+
+const testConfig = `
+critical_resources:
+  - azurerm_key_vault
+`
+
+// newTestServer function returns the server with the config file, which keeps azurerm_key_vault resources
+func newTestServer(t *testing.T, options Options) *Server {
+	options.ConfigFile = path.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(options.ConfigFile, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+type ServerTestSuite struct {
+	suite.Suite
+
+	server *Server
+}
+
+func (ts *ServerTestSuite) SetupTest() {
+	ts.server = newTestServer(ts.T(), Options{MaxRequestSize: 4096})
+}
+
+func (ts *ServerTestSuite) send(method string, target string, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	ts.server.Handler().ServeHTTP(recorder, httptest.NewRequest(method, target, bytes.NewBufferString(body)))
+
+	return recorder
+}
+
+func (ts *ServerTestSuite) TestVerdictOfSinglePlan() {
+	response := ts.send(http.MethodPost, "/api/v1/verdict", testPlan)
+
+	var summary report.Summary
+	assert.Equal(ts.T(), http.StatusOK, response.Code)                              //nolint:typecheck
+	assert.Nil(ts.T(), json.Unmarshal(response.Body.Bytes(), &summary))             //nolint:typecheck
+	assert.Equal(ts.T(), report.VerdictBlocked, summary.Verdict)                    //nolint:typecheck
+	assert.Equal(ts.T(), []string{defaultModuleName}, summary.Modules)              //nolint:typecheck
+	assert.Equal(ts.T(), 1, summary.BlockedRemovals)                                //nolint:typecheck
+	assert.Equal(ts.T(), "application/json", response.Header().Get("Content-Type")) //nolint:typecheck
+}
+
+func (ts *ServerTestSuite) TestJsonReportOfManyPlans() {
+	body := `{"plans": {"app": {"format_version": "1.2", "resource_changes": [
+		{"address": "null_resource.a", "type": "null_resource", "name": "a", "change": {"actions": ["delete"]}}
+	]}, "db": {"format_version": "1.2"}}}`
+
+	response := ts.send(http.MethodPost, "/api/v1/report", body)
+
+	var document report.JsonReport
+	assert.Equal(ts.T(), http.StatusOK, response.Code)                         //nolint:typecheck
+	assert.Nil(ts.T(), json.Unmarshal(response.Body.Bytes(), &document))       //nolint:typecheck
+	assert.Equal(ts.T(), report.VerdictWarnings, document.Summary.Verdict)     //nolint:typecheck
+	assert.Equal(ts.T(), []string{"app", "db"}, document.Summary.Modules)      //nolint:typecheck
+	assert.Len(ts.T(), document.Resources, 1)                                  //nolint:typecheck
+	assert.Equal(ts.T(), report.RemovalAllowed, document.Resources[0].Removal) //nolint:typecheck
+}
+
+func (ts *ServerTestSuite) TestMarkdownReport() {
+	response := ts.send(http.MethodPost, "/api/v1/report?format=github_markdown&module=prod", testPlan)
+
+	assert.Equal(ts.T(), http.StatusOK, response.Code)                                                                 //nolint:typecheck
+	assert.Equal(ts.T(), "text/markdown; charset=utf-8", response.Header().Get("Content-Type"))                        //nolint:typecheck
+	assert.Contains(ts.T(), response.Body.String(), "azurerm_key_vault")                                               //nolint:typecheck
+	assert.Equal(ts.T(), http.StatusBadRequest, ts.send(http.MethodPost, "/api/v1/report?format=nope", testPlan).Code) //nolint:typecheck
+}
+
+func (ts *ServerTestSuite) TestBadRequests() {
+	assert.Equal(ts.T(), http.StatusBadRequest, ts.send(http.MethodPost, "/api/v1/verdict", "[]").Code)            //nolint:typecheck
+	assert.Equal(ts.T(), http.StatusBadRequest, ts.send(http.MethodPost, "/api/v1/verdict", `{"plans": {}}`).Code) //nolint:typecheck
+	assert.Equal(ts.T(), http.StatusBadRequest, ts.send(http.MethodPost, "/api/v1/verdict", `{"a": 1}`).Code)      //nolint:typecheck
+
+	large := `{"format_version": "1.2", "padding": "` + string(bytes.Repeat([]byte("x"), 5000)) + `"}`
+	assert.Equal(ts.T(), http.StatusRequestEntityTooLarge, ts.send(http.MethodPost, "/api/v1/verdict", large).Code) //nolint:typecheck
+}
+
+func (ts *ServerTestSuite) TestHealthAndReadiness() {
+	assert.Equal(ts.T(), http.StatusOK, ts.send(http.MethodGet, "/healthz", "").Code) //nolint:typecheck
+
+	response := ts.send(http.MethodGet, "/readyz", "")
+	assert.Equal(ts.T(), http.StatusOK, response.Code)                 //nolint:typecheck
+	assert.Contains(ts.T(), response.Body.String(), `"ready"`)         //nolint:typecheck
+	assert.NotContains(ts.T(), response.Body.String(), "config_error") //nolint:typecheck
+}
+
+func (ts *ServerTestSuite) TestConfigReload() {
+	configFile := ts.server.options.ConfigFile
+	modTime := time.Now().Add(time.Minute)
+
+	assert.Nil(ts.T(), os.WriteFile(configFile, []byte("critical_resources: [all]\nallowed_removals: [azurerm_key_vault]\n"), 0644)) //nolint:typecheck
+	assert.Nil(ts.T(), os.Chtimes(configFile, modTime, modTime))                                                                     //nolint:typecheck
+	ts.server.reloadConfigIfChanged()
+
+	var summary report.Summary
+	// null_resource is not allowed to be removed anymore
+	_ = json.Unmarshal(ts.send(http.MethodPost, "/api/v1/verdict", testPlan).Body.Bytes(), &summary)
+	assert.Equal(ts.T(), 1, summary.BlockedRemovals) //nolint:typecheck
+	assert.Equal(ts.T(), 1, summary.AllowedRemovals) //nolint:typecheck
+
+	modTime = modTime.Add(time.Minute)
+	assert.Nil(ts.T(), os.WriteFile(configFile, []byte("critical_resources: [all, azurerm_key_vault]\n"), 0644)) //nolint:typecheck
+	assert.Nil(ts.T(), os.Chtimes(configFile, modTime, modTime))                                                 //nolint:typecheck
+	ts.server.reloadConfigIfChanged()
+
+	// The invalid config is not applied
+	_ = json.Unmarshal(ts.send(http.MethodPost, "/api/v1/verdict", testPlan).Body.Bytes(), &summary)
+	assert.Equal(ts.T(), 1, summary.AllowedRemovals)                                              //nolint:typecheck
+	assert.Contains(ts.T(), ts.send(http.MethodGet, "/readyz", "").Body.String(), "config_error") //nolint:typecheck
+}
+
+func (ts *ServerTestSuite) TestIncludedAndOverrideFilesReload() {
+	folder := path.Dir(ts.server.options.ConfigFile)
+	write := func(fileName string, content string, modTime time.Time) {
+		assert.Nil(ts.T(), os.MkdirAll(path.Dir(fileName), 0755))         //nolint:typecheck
+		assert.Nil(ts.T(), os.WriteFile(fileName, []byte(content), 0644)) //nolint:typecheck
+		assert.Nil(ts.T(), os.Chtimes(fileName, modTime, modTime))        //nolint:typecheck
+	}
+	blocked := func(module string) int {
+		var summary report.Summary
+		_ = json.Unmarshal(ts.send(http.MethodPost, "/api/v1/verdict?module="+module, testPlan).Body.Bytes(), &summary)
+
+		return summary.BlockedRemovals
+	}
+
+	modTime := time.Now()
+	included := path.Join(folder, "common.yml")
+	override := path.Join(folder, "plans", "app", ".tf-plan-reporter.yml")
+	write(included, "critical_resources: [azurerm_key_vault]\n", modTime)
+	write(override, "critical_resources: [null_resource]\n", modTime)
+	write(ts.server.options.ConfigFile, "include: [common.yml]\nterraform_plan_search_folder: "+path.Join(folder, "plans")+"\n", modTime)
+	assert.Nil(ts.T(), ts.server.loadConfig()) //nolint:typecheck
+
+	assert.Equal(ts.T(), 1, blocked("db"))  //nolint:typecheck
+	assert.Equal(ts.T(), 2, blocked("app")) //nolint:typecheck
+
+	// Only the included file is changed
+	modTime = modTime.Add(time.Minute)
+	write(included, "critical_resources: [all]\nallowed_removals: [azurerm_key_vault, null_resource]\n", modTime)
+	ts.server.reloadConfigIfChanged()
+
+	assert.Equal(ts.T(), 0, blocked("db"))  //nolint:typecheck
+	assert.Equal(ts.T(), 1, blocked("app")) //nolint:typecheck
+
+	// Only the override file is changed
+	modTime = modTime.Add(time.Minute)
+	write(override, "allowed_removals: [null_resource]\n", modTime)
+	ts.server.reloadConfigIfChanged()
+
+	assert.Equal(ts.T(), 0, blocked("app")) //nolint:typecheck
+}
+
+func (ts *ServerTestSuite) TestNewOverrideFileReload() {
+	folder := path.Dir(ts.server.options.ConfigFile)
+	plans := path.Join(folder, "plans")
+	assert.Nil(ts.T(), os.MkdirAll(path.Join(plans, "app"), 0755))                                                                                                     //nolint:typecheck
+	assert.Nil(ts.T(), os.WriteFile(ts.server.options.ConfigFile, []byte("critical_resources: [azurerm_key_vault]\nterraform_plan_search_folder: "+plans+"\n"), 0644)) //nolint:typecheck
+	assert.Nil(ts.T(), ts.server.loadConfig())                                                                                                                         //nolint:typecheck
+
+	var summary report.Summary
+	_ = json.Unmarshal(ts.send(http.MethodPost, "/api/v1/verdict?module=app", testPlan).Body.Bytes(), &summary)
+	assert.Equal(ts.T(), 1, summary.BlockedRemovals) //nolint:typecheck
+
+	// The override file didn't exist, when the config was loaded
+	assert.Nil(ts.T(), os.WriteFile(path.Join(plans, "app", ".tf-plan-reporter.yml"), []byte("critical_resources: [null_resource]\n"), 0644)) //nolint:typecheck
+	ts.server.reloadConfigIfChanged()
+
+	_ = json.Unmarshal(ts.send(http.MethodPost, "/api/v1/verdict?module=app", testPlan).Body.Bytes(), &summary)
+	assert.Equal(ts.T(), 2, summary.BlockedRemovals) //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestServer(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
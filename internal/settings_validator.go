@@ -18,25 +18,76 @@ const (
 	errMessageCriticalAndAllowedEmptyBoth = "either config file parameter 'critical_resources' list or 'allowed_removals' list must be specified"
 	errMessagePathShouldNotBeFolder       = "path should not be folder, but regular file instead: '%s'"
 	errMessagePathShouldNotBeFile         = "path should not be regular file, but folder instead: '%s'"
+	errMessageUnknownNotification         = "config file parameter 'notifications' has unknown type '%s', it should be either 'slack' or 'teams'"
+	errMessageUnknownVerdict              = "config file parameter 'on_verdicts' has unknown verdict '%s', it should be one of: passed, warnings, blocked"
+	errMessageIgnoreRuleWithoutAttributes = "config file parameter 'ignore_rules' has rule of resource type '%s' without 'attributes'"
 	errMessageTfProviderFolderAbsent = "terraform providers folder (.terraform/providers) was not found in current working directory, which is mandatory if config file parameter 'not_use_chdir': true"
 )
 
 func Validate(settings *config.AppConfig) error {
 	cwd, err := os.Getwd()
 	if err != nil {
-		log.Fatal("Could not get current working dir")
+		return fmt.Errorf("could not get current working dir: %w", err)
 	}
 
 	// First block of checks
 	if err := errors.Join(
-		checkIfParameterWasSpecified(settings.TfCmdBinaryFile, fmt.Sprintf(errMessageEmptyParam, "terraform_binary_file")),
-		checkIfParameterWasSpecified(settings.TfPlanFileBasename, fmt.Sprintf(errMessageEmptyParam, "terraform_plan_file_basename")),
-		checkIfParameterWasSpecified(settings.SearchFolder, fmt.Sprintf(errMessageEmptyParam, "terraform_plan_search_folder")),
+		locate(settings, "terraform_binary_file", checkIfParameterWasSpecified(settings.TfCmdBinaryFile, fmt.Sprintf(errMessageEmptyParam, "terraform_binary_file"))),
+		locate(settings, "terraform_plan_file_basename", checkIfParameterWasSpecified(settings.TfPlanFileBasename, fmt.Sprintf(errMessageEmptyParam, "terraform_plan_file_basename"))),
+		locate(settings, "terraform_plan_search_folder", checkIfParameterWasSpecified(settings.SearchFolder, fmt.Sprintf(errMessageEmptyParam, "terraform_plan_search_folder"))),
+		ValidatePolicy(settings),
+	); err != nil {
+		return err
+	}
+
+	//Replacing of relative TF command path to absolute one if it's required
+	if !path.IsAbs(settings.TfCmdBinaryFile) {
+		settings.TfCmdBinaryFile = path.Join(cwd, settings.TfCmdBinaryFile)
+	}
+
+	//Replacing of relative SearchFolder path to absolute one if it's required
+	if !path.IsAbs(settings.SearchFolder) {
+		settings.SearchFolder = path.Join(cwd, settings.SearchFolder)
+	}
+
+	// Second block of checks
+	if err := errors.Join(
+		locate(settings, "terraform_binary_file", checkIfPathExists(settings.TfCmdBinaryFile, true)),
+		locate(settings, "terraform_plan_search_folder", checkIfPathExists(settings.SearchFolder, false)),
+		func() error {
+			if len(settings.TemplatesDir) > 0 {
+				return locate(settings, "templates_dir", checkIfPathExists(settings.TemplatesDir, false))
+			}
+
+			return nil
+		}(),
+		func() error { 	//Similar checking, if settings.NotUseTfChDirArg == false, will be further once all tf-plan files found
+			if settings.NotUseTfChDirArg {
+				log.Debug("Checking if Terraform providers folder exists in current folder in advance, 'not_use_chdir': true")
+
+				if !processing.TfProviderFolderExist(cwd) {
+					return errors.New(errMessageTfProviderFolderAbsent)
+				}
+			}
+
+			return nil
+		}(),
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidatePolicy function checks only the removal policy related parameters of config file, e.g. for the server mode,
+// where terraform binary and plan files are not needed
+func ValidatePolicy(settings *config.AppConfig) error {
+	return errors.Join(
 		func() error {
 			log.Debug("Checking if config file parameter 'critical_resources' is 'all' and there is only 1 item then ")
 
 			if settings.IsAllCriticalSpecified && len(settings.CriticalResources) > 1 {
-				return errors.New(errMessageAllOnlyOne)
+				return locate(settings, "critical_resources", errors.New(errMessageAllOnlyOne))
 			}
 
 			return nil
@@ -46,12 +97,14 @@ func Validate(settings *config.AppConfig) error {
 
 			if !settings.IsAllCriticalSpecified && len(settings.CriticalResources) > 0 {
 				if len(settings.AllowedRemovals) > 0 {
-					return errors.New(errMessageCriticalAndAllowedFullBoth)
+					return locate(settings, "allowed_removals", errors.New(errMessageCriticalAndAllowedFullBoth))
 				}
 			}
 
 			return nil
 		}(),
+		locate(settings, "notifications", checkNotifications(settings.Notifications)),
+		locate(settings, "ignore_rules", checkIgnoreRules(settings.IgnoreRules)),
 		func() error {
 			log.Debug("Checking if config file parameters 'critical_resources' & 'allowed_removals' are not empty both")
 
@@ -61,40 +114,57 @@ func Validate(settings *config.AppConfig) error {
 
 			return nil
 		}(),
-	); err != nil {
+	)
+}
+
+// locate function prefixes the error with the location, where the config parameter is set, e.g. `config.yml:12`
+func locate(settings *config.AppConfig, name string, err error) error {
+	location, ok := settings.Locations[name]
+	if err == nil || !ok {
 		return err
 	}
 
-	//Replacing of relative TF command path to absolute one if it's required
-	if !path.IsAbs(settings.TfCmdBinaryFile) {
-		settings.TfCmdBinaryFile = path.Join(cwd, settings.TfCmdBinaryFile)
-	}
+	return fmt.Errorf("%s: %w", location, err)
+}
 
-	//Replacing of relative SearchFolder path to absolute one if it's required
-	if !path.IsAbs(settings.SearchFolder) {
-		settings.SearchFolder = path.Join(cwd, settings.SearchFolder)
-	}
+func checkNotifications(notifications []config.Notification) error {
+	log.Debug("Checking if config file parameter 'notifications' has valid items")
 
-	// Second block of checks
-	if err := errors.Join(
-		checkIfPathExists(settings.TfCmdBinaryFile, true),
-		checkIfPathExists(settings.SearchFolder, false),
-		func() error { 	//Similar checking, if settings.NotUseTfChDirArg == false, will be further once all tf-plan files found
-			if settings.NotUseTfChDirArg {
-				log.Debug("Checking if Terraform providers folder exists in current folder in advance, 'not_use_chdir': true")
+	var errs []error
+	for _, notification := range notifications {
+		switch notification.Type {
+		case "slack", "teams":
+		default:
+			errs = append(errs, fmt.Errorf(errMessageUnknownNotification, notification.Type))
+		}
 
-				if !processing.TfProviderFolderExist(cwd) {
-					return errors.New(errMessageTfProviderFolderAbsent)
-				}
+		errs = append(errs, checkIfParameterWasSpecified(notification.WebhookUrl, fmt.Sprintf(errMessageEmptyParam, "webhook_url")))
+
+		for _, verdict := range notification.OnVerdicts {
+			switch verdict {
+			case "passed", "warnings", "blocked":
+			default:
+				errs = append(errs, fmt.Errorf(errMessageUnknownVerdict, verdict))
 			}
+		}
+	}
 
-			return nil
-		}(),
-	); err != nil {
-		return err
+	return errors.Join(errs...)
+}
+
+func checkIgnoreRules(rules []config.IgnoreRule) error {
+	log.Debug("Checking if config file parameter 'ignore_rules' has valid items")
+
+	var errs []error
+	for _, rule := range rules {
+		errs = append(errs, checkIfParameterWasSpecified(rule.ResourceType, fmt.Sprintf(errMessageEmptyParam, "resource_type")))
+
+		if len(rule.Attributes) == 0 {
+			errs = append(errs, fmt.Errorf(errMessageIgnoreRuleWithoutAttributes, rule.ResourceType))
+		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 func checkIfParameterWasSpecified(parameterValue string, errMsg string) error {
@@ -92,6 +92,20 @@ func (ts *SettingsValidatorTestSuite) TestIfParamsCriticalAndAllowedEmptyBothHan
 	assert.ErrorContains(ts.T(), err, errMessageCriticalAndAllowedEmptyBoth, "Error message must be:  '%s'", errMessageCriticalAndAllowedEmptyBoth) //nolint:typecheck
 
 }
+func (ts *SettingsValidatorTestSuite) TestIfIgnoreRuleWithoutAttributesHandled() {
+	ts.settings.IgnoreRules = []config.IgnoreRule{{ResourceType: "azurerm_storage_account"}}
+	err := Validate(ts.settings)
+
+	assert.ErrorContains(ts.T(), err, fmt.Sprintf(errMessageIgnoreRuleWithoutAttributes, "azurerm_storage_account")) //nolint:typecheck
+}
+func (ts *SettingsValidatorTestSuite) TestIfErrorIsLocated() {
+	ts.settings.IsAllCriticalSpecified = true
+	ts.settings.CriticalResources = []string{"all", "resource1"}
+	ts.settings.Locations = map[string]string{"critical_resources": "config.yml:4"}
+	err := Validate(ts.settings)
+
+	assert.ErrorContains(ts.T(), err, "config.yml:4: "+errMessageAllOnlyOne) //nolint:typecheck
+}
 func (ts *SettingsValidatorTestSuite) TestIfAbsenceOfBinaryFileHandled() {
 	ts.settings.TfCmdBinaryFile = ts.settings.TfCmdBinaryFile + "_absent"
 	err := Validate(ts.settings) //Checking first case: if the path DOES NOT exist
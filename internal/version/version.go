@@ -0,0 +1,6 @@
+package version
+
+var (
+	// Version of the App. It's set up during the build with help of `-ldflags "-X ..."`, see magefile.go
+	Version = "dev"
+)
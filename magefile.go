@@ -16,6 +16,7 @@ import (
 const (
 	mainAppPackagePath = "./cmd/tf-plan-reporter"
 	auxAppPackagePath  = "./cmd/test-plan-reader"
+	versionVariable    = "github.com/arshvin/tf-plan-reporter/internal/version.Version"
 )
 
 // Default target to run when none is specified
@@ -69,7 +70,20 @@ func BuildTestApp() error {
 
 // Executes golang build command
 func build(envs map[string]string, binName, packPath string) error {
-	return sh.RunWith(envs, "go", "build", "-o", binName, packPath)
+	return sh.RunWith(envs, "go", "build", "-ldflags", fmt.Sprintf("-X %s=%s", versionVariable, appVersion()), "-o", binName, packPath)
+}
+
+// Returns version of the App: either from APP_VERSION environment variable, or the latest git tag
+func appVersion() string {
+	if version := os.Getenv("APP_VERSION"); version != "" {
+		return version
+	}
+
+	if version, err := sh.Output("git", "describe", "--tags", "--always"); err == nil && version != "" {
+		return version
+	}
+
+	return "dev"
 }
 
 // Executes golang test command
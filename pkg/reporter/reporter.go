@@ -0,0 +1,325 @@
+// Package reporter is the Go API of tf-plan-reporter for embedding it into other tools: collecting of TF plan files,
+// evaluating of the removal policy and rendering of the report. Unlike the App, the functions never exit, they return
+// errors instead. The decision maker evaluating the plans is created for the config and passed explicitly, so several
+// configs might be used at the same time. All types of the API are defined by the package, the internal ones of the
+// App are converted at its boundary
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"time"
+
+	tfJson "github.com/hashicorp/terraform-json"
+
+	"github.com/arshvin/tf-plan-reporter/internal"
+	"github.com/arshvin/tf-plan-reporter/internal/config"
+	"github.com/arshvin/tf-plan-reporter/internal/processing"
+	"github.com/arshvin/tf-plan-reporter/internal/report"
+)
+
+const (
+	VerdictPassed   = report.VerdictPassed
+	VerdictWarnings = report.VerdictWarnings
+	VerdictBlocked  = report.VerdictBlocked
+
+	RemovalBlocked  = report.RemovalBlocked
+	RemovalAllowed  = report.RemovalAllowed
+	RemovalAccepted = report.RemovalAccepted
+	RemovalApproved = report.RemovalApproved
+)
+
+// Config is the config of the App, the same as read from the config file. It's passed to the functions as is
+type Config struct {
+	config *config.AppConfig
+}
+
+// DecisionMaker evaluates the planned changes by the policy, the baseline and approvals of the config
+type DecisionMaker struct {
+	dm *processing.DecisionMaker
+}
+
+// Plans are the planned changes of all collected modules
+type Plans struct {
+	data *processing.ConsolidatedJson
+}
+
+// LoadOptions are the parameters of loading of the config, applied on top of the config file
+type LoadOptions struct {
+	Profile   string     //Profile of the config file, if any
+	Approvals []Approval //Approvals of removals for the run, e.g. supplied by pull request labels
+}
+
+// Approval releases the removals of matching resources
+type Approval struct {
+	Address  string `json:"address,omitempty"` //Address of the resource, `*` matches any characters. Any address if empty
+	Module   string `json:"module,omitempty"`  //Module of the resource, `*` matches any characters. Any module if empty
+	Approver string `json:"approver"`          //Who has approved the removals
+	Reason   string `json:"reason,omitempty"`  //Why the removals are approved
+}
+
+// Resource is the planned change of the resource
+type Resource struct {
+	Address       string    `json:"address"`
+	Module        string    `json:"module"`
+	Type          string    `json:"type"`
+	Name          string    `json:"name"`
+	Index         string    `json:"index,omitempty"`
+	Action        string    `json:"action"`                  //create, update, delete, replace, read or no-op
+	Noise         bool      `json:"noise,omitempty"`         //The update changes ignored attributes only, so it's reported as unchanged
+	Removal       string    `json:"removal,omitempty"`       //Verdict over evaluated deleted and replaced resources: blocked, allowed, accepted or approved
+	Justification string    `json:"justification,omitempty"` //Justification of the baseline entry accepting the change, if any
+	Approval      *Approval `json:"approval,omitempty"`      //Approval releasing the removal, if any
+}
+
+// Summary is the counts of planned changes and the verdict of the gate
+type Summary struct {
+	ToAdd                 int      `json:"to_add"`                  //Amount of created resources, excluding replaced ones
+	ToChange              int      `json:"to_change"`               //Amount of updated resources
+	ToDestroy             int      `json:"to_destroy"`              //Amount of deleted resources, excluding replaced ones
+	ToReplace             int      `json:"to_replace"`              //Amount of replaced (deleted and created) resources
+	Unchanged             int      `json:"unchanged"`               //Amount of unchanged resources, including suppressed ones
+	SuppressedUpdates     int      `json:"suppressed_updates"`      //Amount of updates of ignored attributes only, reported as unchanged
+	ToRead                int      `json:"to_read"`                 //Amount of data sources to be read
+	Modules               []string `json:"modules"`                 //All parsed modules
+	ModulesWithoutChanges []string `json:"modules_without_changes"` //Modules without any planned changes
+	BlockedRemovals       int      `json:"blocked_removals"`        //Amount of deleted resources, which are not allowed to be removed
+	AllowedRemovals       int      `json:"allowed_removals"`        //Amount of deleted resources, which are allowed to be removed
+	ApprovedRemovals      int      `json:"approved_removals"`       //Amount of deleted resources, whose removals are approved
+	AcceptedChanges       int      `json:"accepted_changes"`        //Amount of planned changes accepted by baseline
+	ExpiredAcceptances    []string `json:"expired_acceptances"`     //Descriptions of expired baseline entries
+	Verdict               string   `json:"verdict"`                 //Result of the gate: passed, warnings or blocked
+	Version               string   `json:"version"`                 //Version of the reporter
+}
+
+// Explanation is the trail of decisions taken over the planned change of the resource
+type Explanation struct {
+	Resource Resource
+	Removal  bool     //Whether the gate applies to the change at all, i.e. the resource is deleted or replaced
+	Allowed  bool     //Whether the change is allowed by the gate
+	Reasons  []string //Decisions in order of their evaluation
+}
+
+// CollectOptions are the parameters of collecting of TF plan files, the same as the config file ones
+type CollectOptions struct {
+	SearchFolder      string //Common parent folder of plan files
+	PlanBasename      string //Base name of terraform generated binary plan files
+	TerraformBinary   string //Name or path of terraform command
+	NotUseChDir       bool   //Run terraform in current working directory instead of the folders of plan files
+	FailIfNoPlanFound bool   //Return the error, if there are no plan files in the search folder
+}
+
+// LoadConfig function reads the config file together with its included files, applies the profile of the options (if
+// any) and `TFPR_*` environment variables, and checks the removal policy of it. The file name might be empty, if all
+// parameters are given by the environment variables
+func LoadConfig(fileName string, options LoadOptions) (*Config, error) {
+	cfg, err := config.LoadWithOptions(fileName, config.Options{Profile: options.Profile})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, approval := range options.Approvals {
+		if len(approval.Approver) == 0 || len(approval.Address) == 0 && len(approval.Module) == 0 {
+			return nil, fmt.Errorf("approval must have the approver and either address or module: %+v", approval)
+		}
+
+		cfg.Approvals = append(cfg.Approvals, &config.Approval{
+			Address:  approval.Address,
+			Module:   approval.Module,
+			Approver: approval.Approver,
+			Reason:   approval.Reason,
+		})
+	}
+
+	if err := internal.ValidatePolicy(cfg); err != nil {
+		return nil, err
+	}
+
+	return &Config{config: cfg}, nil
+}
+
+// NewDecisionMaker function returns the decision maker evaluating the planned changes by the config
+func NewDecisionMaker(cfg *Config) *DecisionMaker {
+	return &DecisionMaker{dm: processing.NewDecisionMaker(cfg.config)}
+}
+
+// OptionsOf function returns the options of collecting of TF plan files given by the config file parameters
+func OptionsOf(cfg *Config) CollectOptions {
+	return CollectOptions{
+		SearchFolder:      cfg.config.SearchFolder,
+		PlanBasename:      cfg.config.TfPlanFileBasename,
+		TerraformBinary:   cfg.config.TfCmdBinaryFile,
+		NotUseChDir:       cfg.config.NotUseTfChDirArg,
+		FailIfNoPlanFound: cfg.config.FailIfNoTfPlanFound,
+	}
+}
+
+// Collect function finds TF plan files in the search folder, reads them by `terraform show` and parses planned changes.
+// The decision maker detects updates of ignored attributes, it might be nil if there are no ignore rules
+func Collect(options CollectOptions, dm *DecisionMaker) (*Plans, error) {
+	if len(options.SearchFolder) == 0 || len(options.PlanBasename) == 0 || len(options.TerraformBinary) == 0 {
+		return nil, fmt.Errorf("search folder, plan base name and terraform binary are mandatory options")
+	}
+
+	searchFolder, err := filepath.Abs(options.SearchFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := processing.CollectBinaryData(searchFolder, options.PlanBasename, options.TerraformBinary, options.NotUseChDir, options.FailIfNoPlanFound, dm.internal())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plans{data: data}, nil
+}
+
+// ReadPlanJson function reads the plan already converted to JSON by `terraform show -json`
+func ReadPlanJson(fileName string) (*tfJson.Plan, error) {
+	return processing.ReadPlanJson(fileName)
+}
+
+// Parse function parses planned changes of the plans keyed by module names, e.g. the ones read by ReadPlanJson. The
+// decision maker might be nil, the same way as for Collect
+func Parse(plans map[string]*tfJson.Plan, dm *DecisionMaker) *Plans {
+	data := new(processing.ConsolidatedJson)
+	for module, plan := range plans {
+		data.Parse(module, plan, dm.internal())
+	}
+
+	return &Plans{data: data}
+}
+
+// Evaluate function decides over every planned change of the plans, stores the verdicts on the resources and returns
+// the counts of planned changes with the verdict of the gate. It should be called before rendering of the plans
+func Evaluate(plans *Plans, dm *DecisionMaker) *Summary {
+	dm.dm.Evaluate(plans.data)
+
+	return summaryOf(report.NewSummary(plans.data, time.Time{}))
+}
+
+// Explain function returns the decisions taken over the planned change of the resource with the address, one per module
+// it's found in. The plans must be evaluated by Evaluate before, the verdicts are not evaluated again
+func Explain(plans *Plans, dm *DecisionMaker, address string) ([]Explanation, error) {
+	explanations, err := dm.dm.Explain(plans.data, address)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Explanation
+	for _, explanation := range explanations {
+		result = append(result, Explanation{
+			Resource: resourceOf(explanation.Resource),
+			Removal:  explanation.Removal,
+			Allowed:  explanation.Allowed,
+			Reasons:  slices.Clone(explanation.Reasons),
+		})
+	}
+
+	return result, nil
+}
+
+// Render function renders the report of the evaluated plans in the format (e.g. `stdout`, `github_markdown`, `html`,
+// `json` or the one of user supplied templates of `templates_dir`) and writes it to the writer. The report is fitted
+// into the size limit of the format
+func Render(w io.Writer, plans *Plans, cfg *Config, format string) error {
+	content, err := report.RenderOutput(plans.data, cfg.config, config.ReportOutput{Format: format})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, content)
+
+	return err
+}
+
+// Modules method returns the names of all parsed modules
+func (p *Plans) Modules() []string {
+	return slices.Clone(p.data.Modules)
+}
+
+// Resources method returns the planned changes of all resources. Replaced resources are listed once. The verdicts over
+// removals are filled after evaluation of the plans only
+func (p *Plans) Resources() []Resource {
+	var result []Resource
+
+	seen := make(map[*processing.ResourceData]bool)
+	for _, items := range [][]*processing.ResourceData{p.data.Created, p.data.Updated, p.data.Deleted, p.data.Unchanged, p.data.Read} {
+		for _, item := range items {
+			if !seen[item] {
+				seen[item] = true
+				result = append(result, resourceOf(item))
+			}
+		}
+	}
+
+	return result
+}
+
+// internal method returns the decision maker of the App, nil is kept as is
+func (dm *DecisionMaker) internal() *processing.DecisionMaker {
+	if dm == nil {
+		return nil
+	}
+
+	return dm.dm
+}
+
+func resourceOf(rd *processing.ResourceData) Resource {
+	resource := Resource{
+		Address: rd.Address,
+		Module:  rd.Module,
+		Type:    rd.Type,
+		Name:    rd.Name,
+		Index:   rd.Index,
+		Action:  rd.ActionLabel(),
+		Noise:   rd.Noise,
+	}
+
+	accepted := rd.AcceptedBy()
+	if accepted != nil {
+		resource.Justification = accepted.Justification
+	}
+
+	if rd.Verdict == nil || !(rd.Actions.Delete() || rd.Actions.Replace()) {
+		return resource
+	}
+
+	approval := rd.ApprovedBy()
+	switch {
+	case accepted != nil:
+		resource.Removal = RemovalAccepted
+	case approval != nil:
+		resource.Removal = RemovalApproved
+		resource.Approval = &Approval{Address: approval.Address, Module: approval.Module, Approver: approval.Approver, Reason: approval.Reason}
+	case rd.IsAllowed():
+		resource.Removal = RemovalAllowed
+	default:
+		resource.Removal = RemovalBlocked
+	}
+
+	return resource
+}
+
+func summaryOf(summary *report.Summary) *Summary {
+	return &Summary{
+		ToAdd:                 summary.ToAdd,
+		ToChange:              summary.ToChange,
+		ToDestroy:             summary.ToDestroy,
+		ToReplace:             summary.ToReplace,
+		Unchanged:             summary.Unchanged,
+		SuppressedUpdates:     summary.SuppressedUpdates,
+		ToRead:                summary.ToRead,
+		Modules:               slices.Clone(summary.Modules),
+		ModulesWithoutChanges: slices.Clone(summary.ModulesWithoutChanges),
+		BlockedRemovals:       summary.BlockedRemovals,
+		AllowedRemovals:       summary.AllowedRemovals,
+		ApprovedRemovals:      summary.ApprovedRemovals,
+		AcceptedChanges:       summary.AcceptedChanges,
+		ExpiredAcceptances:    slices.Clone(summary.ExpiredAcceptances),
+		Verdict:               summary.Verdict,
+		Version:               summary.Version,
+	}
+}
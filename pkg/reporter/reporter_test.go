@@ -0,0 +1,132 @@
+package reporter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	tfJson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arshvin/tf-plan-reporter/pkg/reporter"
+)
+
+// ReporterTestSuite uses the exported API only, the same way as tools embedding the package do
+type ReporterTestSuite struct {
+	suite.Suite
+
+	tmpDir string
+	plans  map[string]*tfJson.Plan
+}
+
+func (ts *ReporterTestSuite) SetupTest() {
+	ts.tmpDir = ts.T().TempDir() //nolint:typecheck
+
+	ts.plans = map[string]*tfJson.Plan{
+		"prod": {ResourceChanges: []*tfJson.ResourceChange{
+			{Address: "azurerm_key_vault.main", Type: "azurerm_key_vault", Name: "main", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionDelete}}},
+			{Address: "null_resource.a", Type: "null_resource", Name: "a", Change: &tfJson.Change{Actions: tfJson.Actions{tfJson.ActionCreate}}},
+		}},
+	}
+}
+
+func (ts *ReporterTestSuite) writeConfig(content string) string {
+	fileName := path.Join(ts.tmpDir, "config.yml")
+	if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+		assert.FailNow(ts.T(), "Could not create file for test: %s", fileName) //nolint:typecheck
+	}
+
+	return fileName
+}
+
+func (ts *ReporterTestSuite) config(content string, options reporter.LoadOptions) *reporter.Config {
+	cfg, err := reporter.LoadConfig(ts.writeConfig(content), options)
+	assert.NoError(ts.T(), err) //nolint:typecheck
+
+	return cfg
+}
+
+func (ts *ReporterTestSuite) TestSeveralConfigs() {
+	strict := reporter.NewDecisionMaker(ts.config("critical_resources: [azurerm_key_vault]\n", reporter.LoadOptions{}))
+	relaxed := reporter.NewDecisionMaker(ts.config("critical_resources: [azurerm_storage_account]\n", reporter.LoadOptions{}))
+
+	assert.Equal(ts.T(), reporter.VerdictBlocked, reporter.Evaluate(reporter.Parse(ts.plans, strict), strict).Verdict)    //nolint:typecheck
+	assert.Equal(ts.T(), reporter.VerdictWarnings, reporter.Evaluate(reporter.Parse(ts.plans, relaxed), relaxed).Verdict) //nolint:typecheck
+}
+
+func (ts *ReporterTestSuite) TestResourcesAndApprovals() {
+	approval := reporter.Approval{Address: "azurerm_key_vault.main", Approver: "alice", Reason: "Decommissioned"}
+	dm := reporter.NewDecisionMaker(ts.config("critical_resources: [azurerm_key_vault]\n", reporter.LoadOptions{Approvals: []reporter.Approval{approval}}))
+
+	plans := reporter.Parse(ts.plans, dm)
+	assert.Equal(ts.T(), []string{"prod"}, plans.Modules()) //nolint:typecheck
+	assert.Empty(ts.T(), plans.Resources()[1].Removal)      //nolint:typecheck
+
+	summary := reporter.Evaluate(plans, dm)
+	assert.Equal(ts.T(), reporter.VerdictWarnings, summary.Verdict) //nolint:typecheck
+	assert.Equal(ts.T(), 1, summary.ApprovedRemovals)               //nolint:typecheck
+
+	resources := plans.Resources()
+	assert.Len(ts.T(), resources, 2)                                     //nolint:typecheck
+	assert.Equal(ts.T(), "null_resource.a", resources[0].Address)        //nolint:typecheck
+	assert.Equal(ts.T(), "create", resources[0].Action)                  //nolint:typecheck
+	assert.Empty(ts.T(), resources[0].Removal)                           //nolint:typecheck
+	assert.Equal(ts.T(), reporter.RemovalApproved, resources[1].Removal) //nolint:typecheck
+	assert.Equal(ts.T(), &approval, resources[1].Approval)               //nolint:typecheck
+
+	explanations, err := reporter.Explain(plans, dm, "azurerm_key_vault.main")
+	assert.NoError(ts.T(), err)                                                                                 //nolint:typecheck
+	assert.Len(ts.T(), explanations, 1)                                                                         //nolint:typecheck
+	assert.True(ts.T(), explanations[0].Allowed)                                                                //nolint:typecheck
+	assert.Equal(ts.T(), []string{"the removal is approved by alice: Decommissioned"}, explanations[0].Reasons) //nolint:typecheck
+
+	_, err = reporter.Explain(reporter.Parse(ts.plans, dm), dm, "azurerm_key_vault.main")
+	assert.Error(ts.T(), err) //nolint:typecheck
+
+	_, err = reporter.LoadConfig(ts.writeConfig("critical_resources: [all]\n"), reporter.LoadOptions{Approvals: []reporter.Approval{{Address: "a"}}})
+	assert.Error(ts.T(), err) //nolint:typecheck
+}
+
+func (ts *ReporterTestSuite) TestRender() {
+	cfg := ts.config("critical_resources: [azurerm_key_vault]\n", reporter.LoadOptions{})
+	dm := reporter.NewDecisionMaker(cfg)
+	plans := reporter.Parse(ts.plans, dm)
+	reporter.Evaluate(plans, dm)
+
+	var output bytes.Buffer
+	assert.NoError(ts.T(), reporter.Render(&output, plans, cfg, "json")) //nolint:typecheck
+
+	var document struct {
+		Summary reporter.Summary `json:"summary"`
+	}
+	assert.NoError(ts.T(), json.Unmarshal(output.Bytes(), &document))       //nolint:typecheck
+	assert.Equal(ts.T(), reporter.VerdictBlocked, document.Summary.Verdict) //nolint:typecheck
+	assert.Equal(ts.T(), 1, document.Summary.BlockedRemovals)               //nolint:typecheck
+
+	assert.Error(ts.T(), reporter.Render(&output, plans, cfg, "unknown")) //nolint:typecheck
+}
+
+func (ts *ReporterTestSuite) TestErrorsReturned() {
+	_, err := reporter.LoadConfig(path.Join(ts.tmpDir, "absent.yml"), reporter.LoadOptions{})
+	assert.Error(ts.T(), err) //nolint:typecheck
+
+	_, err = reporter.LoadConfig(ts.writeConfig("critical_resources: [all, azurerm_key_vault]\n"), reporter.LoadOptions{})
+	assert.Error(ts.T(), err) //nolint:typecheck
+
+	_, err = reporter.LoadConfig(ts.writeConfig("critical_resources: [all]\n"), reporter.LoadOptions{Profile: "absent"})
+	assert.Error(ts.T(), err) //nolint:typecheck
+
+	_, err = reporter.Collect(reporter.CollectOptions{SearchFolder: path.Join(ts.tmpDir, "absent"), PlanBasename: "plan.bin", TerraformBinary: "terraform"}, nil)
+	assert.Error(ts.T(), err) //nolint:typecheck
+
+	_, err = reporter.Collect(reporter.CollectOptions{SearchFolder: ts.tmpDir, PlanBasename: "plan.bin", TerraformBinary: "terraform", FailIfNoPlanFound: true}, nil)
+	assert.Error(ts.T(), err) //nolint:typecheck
+}
+
+// Entry point for the test suite
+func TestReporter(t *testing.T) {
+	suite.Run(t, new(ReporterTestSuite))
+}